@@ -23,6 +23,13 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ChatManagementClient interface {
 	GetChat(ctx context.Context, in *GetChatRequest, opts ...grpc.CallOption) (*ChatRoomResponse, error)
+	DeleteChat(ctx context.Context, in *DeleteChatRequest, opts ...grpc.CallOption) (*DeleteChatResponse, error)
+	LeaveAllChats(ctx context.Context, in *LeaveAllChatsRequest, opts ...grpc.CallOption) (*LeaveAllChatsResponse, error)
+	ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersResponse, error)
+	IsMember(ctx context.Context, in *IsMemberRequest, opts ...grpc.CallOption) (*IsMemberResponse, error)
+	ListChatsForUser(ctx context.Context, in *ListChatsForUserRequest, opts ...grpc.CallOption) (*ListChatsForUserResponse, error)
+	GetMemberPermissions(ctx context.Context, in *GetMemberPermissionsRequest, opts ...grpc.CallOption) (*MemberPermissionsResponse, error)
+	WatchMembership(ctx context.Context, in *WatchMembershipRequest, opts ...grpc.CallOption) (ChatManagement_WatchMembershipClient, error)
 }
 
 type chatManagementClient struct {
@@ -42,11 +49,104 @@ func (c *chatManagementClient) GetChat(ctx context.Context, in *GetChatRequest,
 	return out, nil
 }
 
+func (c *chatManagementClient) DeleteChat(ctx context.Context, in *DeleteChatRequest, opts ...grpc.CallOption) (*DeleteChatResponse, error) {
+	out := new(DeleteChatResponse)
+	err := c.cc.Invoke(ctx, "/chat.ChatManagement/DeleteChat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatManagementClient) LeaveAllChats(ctx context.Context, in *LeaveAllChatsRequest, opts ...grpc.CallOption) (*LeaveAllChatsResponse, error) {
+	out := new(LeaveAllChatsResponse)
+	err := c.cc.Invoke(ctx, "/chat.ChatManagement/LeaveAllChats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatManagementClient) ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersResponse, error) {
+	out := new(ListMembersResponse)
+	err := c.cc.Invoke(ctx, "/chat.ChatManagement/ListMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatManagementClient) IsMember(ctx context.Context, in *IsMemberRequest, opts ...grpc.CallOption) (*IsMemberResponse, error) {
+	out := new(IsMemberResponse)
+	err := c.cc.Invoke(ctx, "/chat.ChatManagement/IsMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatManagementClient) ListChatsForUser(ctx context.Context, in *ListChatsForUserRequest, opts ...grpc.CallOption) (*ListChatsForUserResponse, error) {
+	out := new(ListChatsForUserResponse)
+	err := c.cc.Invoke(ctx, "/chat.ChatManagement/ListChatsForUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatManagementClient) GetMemberPermissions(ctx context.Context, in *GetMemberPermissionsRequest, opts ...grpc.CallOption) (*MemberPermissionsResponse, error) {
+	out := new(MemberPermissionsResponse)
+	err := c.cc.Invoke(ctx, "/chat.ChatManagement/GetMemberPermissions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatManagementClient) WatchMembership(ctx context.Context, in *WatchMembershipRequest, opts ...grpc.CallOption) (ChatManagement_WatchMembershipClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ChatManagement_ServiceDesc.Streams[0], "/chat.ChatManagement/WatchMembership", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chatManagementWatchMembershipClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ChatManagement_WatchMembershipClient interface {
+	Recv() (*MembershipEvent, error)
+	grpc.ClientStream
+}
+
+type chatManagementWatchMembershipClient struct {
+	grpc.ClientStream
+}
+
+func (x *chatManagementWatchMembershipClient) Recv() (*MembershipEvent, error) {
+	m := new(MembershipEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ChatManagementServer is the server API for ChatManagement service.
 // All implementations must embed UnimplementedChatManagementServer
 // for forward compatibility
 type ChatManagementServer interface {
 	GetChat(context.Context, *GetChatRequest) (*ChatRoomResponse, error)
+	DeleteChat(context.Context, *DeleteChatRequest) (*DeleteChatResponse, error)
+	LeaveAllChats(context.Context, *LeaveAllChatsRequest) (*LeaveAllChatsResponse, error)
+	ListMembers(context.Context, *ListMembersRequest) (*ListMembersResponse, error)
+	IsMember(context.Context, *IsMemberRequest) (*IsMemberResponse, error)
+	ListChatsForUser(context.Context, *ListChatsForUserRequest) (*ListChatsForUserResponse, error)
+	GetMemberPermissions(context.Context, *GetMemberPermissionsRequest) (*MemberPermissionsResponse, error)
+	WatchMembership(*WatchMembershipRequest, ChatManagement_WatchMembershipServer) error
 	mustEmbedUnimplementedChatManagementServer()
 }
 
@@ -57,6 +157,27 @@ type UnimplementedChatManagementServer struct {
 func (UnimplementedChatManagementServer) GetChat(context.Context, *GetChatRequest) (*ChatRoomResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetChat not implemented")
 }
+func (UnimplementedChatManagementServer) DeleteChat(context.Context, *DeleteChatRequest) (*DeleteChatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteChat not implemented")
+}
+func (UnimplementedChatManagementServer) LeaveAllChats(context.Context, *LeaveAllChatsRequest) (*LeaveAllChatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveAllChats not implemented")
+}
+func (UnimplementedChatManagementServer) ListMembers(context.Context, *ListMembersRequest) (*ListMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMembers not implemented")
+}
+func (UnimplementedChatManagementServer) IsMember(context.Context, *IsMemberRequest) (*IsMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsMember not implemented")
+}
+func (UnimplementedChatManagementServer) ListChatsForUser(context.Context, *ListChatsForUserRequest) (*ListChatsForUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChatsForUser not implemented")
+}
+func (UnimplementedChatManagementServer) GetMemberPermissions(context.Context, *GetMemberPermissionsRequest) (*MemberPermissionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemberPermissions not implemented")
+}
+func (UnimplementedChatManagementServer) WatchMembership(*WatchMembershipRequest, ChatManagement_WatchMembershipServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMembership not implemented")
+}
 func (UnimplementedChatManagementServer) mustEmbedUnimplementedChatManagementServer() {}
 
 // UnsafeChatManagementServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +209,135 @@ func _ChatManagement_GetChat_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChatManagement_DeleteChat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteChatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatManagementServer).DeleteChat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chat.ChatManagement/DeleteChat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatManagementServer).DeleteChat(ctx, req.(*DeleteChatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatManagement_LeaveAllChats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveAllChatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatManagementServer).LeaveAllChats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chat.ChatManagement/LeaveAllChats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatManagementServer).LeaveAllChats(ctx, req.(*LeaveAllChatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatManagement_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatManagementServer).ListMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chat.ChatManagement/ListMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatManagementServer).ListMembers(ctx, req.(*ListMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatManagement_IsMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatManagementServer).IsMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chat.ChatManagement/IsMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatManagementServer).IsMember(ctx, req.(*IsMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatManagement_ListChatsForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChatsForUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatManagementServer).ListChatsForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chat.ChatManagement/ListChatsForUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatManagementServer).ListChatsForUser(ctx, req.(*ListChatsForUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatManagement_GetMemberPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemberPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatManagementServer).GetMemberPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chat.ChatManagement/GetMemberPermissions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatManagementServer).GetMemberPermissions(ctx, req.(*GetMemberPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatManagement_WatchMembership_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchMembershipRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatManagementServer).WatchMembership(m, &chatManagementWatchMembershipServer{stream})
+}
+
+type ChatManagement_WatchMembershipServer interface {
+	Send(*MembershipEvent) error
+	grpc.ServerStream
+}
+
+type chatManagementWatchMembershipServer struct {
+	grpc.ServerStream
+}
+
+func (x *chatManagementWatchMembershipServer) Send(m *MembershipEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ChatManagement_ServiceDesc is the grpc.ServiceDesc for ChatManagement service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,7 +349,37 @@ var ChatManagement_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetChat",
 			Handler:    _ChatManagement_GetChat_Handler,
 		},
+		{
+			MethodName: "DeleteChat",
+			Handler:    _ChatManagement_DeleteChat_Handler,
+		},
+		{
+			MethodName: "LeaveAllChats",
+			Handler:    _ChatManagement_LeaveAllChats_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _ChatManagement_ListMembers_Handler,
+		},
+		{
+			MethodName: "IsMember",
+			Handler:    _ChatManagement_IsMember_Handler,
+		},
+		{
+			MethodName: "ListChatsForUser",
+			Handler:    _ChatManagement_ListChatsForUser_Handler,
+		},
+		{
+			MethodName: "GetMemberPermissions",
+			Handler:    _ChatManagement_GetMemberPermissions_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchMembership",
+			Handler:       _ChatManagement_WatchMembership_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "chat/chat.proto",
 }
@@ -35,3 +35,7 @@ func (r *AuthRepository) FindByLogin(login string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *AuthRepository) Update(user *models.User) error {
+	return r.db.Save(user).Error
+}
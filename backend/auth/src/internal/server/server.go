@@ -5,12 +5,15 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/PolyTechProjects/chaotic_chat/auth/src/gen/go/auth"
 	"github.com/PolyTechProjects/chaotic_chat/auth/src/internal/controller"
 	"github.com/PolyTechProjects/chaotic_chat/auth/src/internal/service"
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -52,10 +55,46 @@ func (s *GRPCServer) Start(l net.Listener) error {
 }
 
 func (s *GRPCServer) Authorize(ctx context.Context, req *auth.AuthorizeRequest) (*auth.AuthorizeResponse, error) {
-	accessToken, userId, err := s.authService.Authorize(req.GetAccessToken())
+	accessToken, user, err := s.authService.Authorize(req.GetAccessToken())
 	if err != nil {
 		slog.Error(err.Error())
 		return nil, status.Error(codes.PermissionDenied, err.Error())
 	}
-	return &auth.AuthorizeResponse{AccessToken: accessToken, UserId: userId.String()}, nil
+	return &auth.AuthorizeResponse{AccessToken: accessToken, UserId: user.Id.String(), Role: user.Role}, nil
+}
+
+func (s *GRPCServer) SetSuspension(ctx context.Context, req *auth.SetSuspensionRequest) (*auth.SetSuspensionResponse, error) {
+	tokens := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(tokens) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata is missing")
+	}
+	_, moderator, err := s.authService.Authorize(tokens[0])
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if !moderator.CanModerate() {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var suspendedUntil *time.Time
+	if req.SuspendedUntil > 0 {
+		until := time.Unix(req.SuspendedUntil, 0)
+		suspendedUntil = &until
+	}
+	user, err := s.authService.SetSuspension(userId, suspendedUntil)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	resp := &auth.SetSuspensionResponse{UserId: user.Id.String()}
+	if user.SuspendedUntil != nil {
+		resp.SuspendedUntil = user.SuspendedUntil.Unix()
+	}
+	return resp, nil
 }
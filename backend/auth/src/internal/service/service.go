@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -13,6 +14,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var ErrUserSuspended = errors.New("user is suspended")
+
 type AuthService struct {
 	AuthRepository *repository.AuthRepository
 	jwtSecretKey   []byte
@@ -58,6 +61,9 @@ func (s *AuthService) Login(login string, password string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if user.IsSuspended(time.Now()) {
+		return "", ErrUserSuspended
+	}
 
 	accessToken, err := s.generateAccessToken(user.Id)
 	slog.Debug(fmt.Sprintf("accessToken: %v", accessToken))
@@ -68,32 +74,49 @@ func (s *AuthService) Login(login string, password string) (string, error) {
 	return accessToken, nil
 }
 
-func (s *AuthService) Authorize(accessToken string) (string, uuid.UUID, error) {
+func (s *AuthService) Authorize(accessToken string) (string, *models.User, error) {
 	var claims jwt.MapClaims
 	_, err := jwt.ParseWithClaims(accessToken, &claims, s.keyFunc)
 	if err != nil {
-		return "", uuid.Nil, err
+		return "", nil, err
 	}
 
 	userId, err := uuid.Parse(claims["sub"].(string))
 	if err != nil {
-		return "", uuid.Nil, err
+		return "", nil, err
 	}
 	slog.Debug(fmt.Sprintf("userId: %v", userId))
 
 	user, err := s.AuthRepository.FindById(userId)
 	if err != nil {
-		return "", uuid.Nil, err
+		return "", nil, err
+	}
+	if user.IsSuspended(time.Now()) {
+		return "", nil, ErrUserSuspended
 	}
 
 	if claims["exp"].(float64) < float64(time.Now().Unix()) {
 		accessToken, err = s.generateAccessToken(user.Id)
 		if err != nil {
-			return "", uuid.Nil, err
+			return "", nil, err
 		}
 	}
 
-	return accessToken, userId, nil
+	return accessToken, user, nil
+}
+
+func (s *AuthService) SetSuspension(userId uuid.UUID, suspendedUntil *time.Time) (*models.User, error) {
+	user, err := s.AuthRepository.FindById(userId)
+	if err != nil {
+		return nil, err
+	}
+	user.SuspendedUntil = suspendedUntil
+	err = s.AuthRepository.Update(user)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info(fmt.Sprintf("User %v suspension set to %v", user.Id, suspendedUntil))
+	return user, nil
 }
 
 func (s *AuthService) ExtractUserId(tokenString string) (string, error) {
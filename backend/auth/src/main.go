@@ -45,7 +45,7 @@ func goTest() {
 		"",
 		" ",
 		"    ",
-		string(0x1F),
+		string(rune(0x1F)),
 	}
 	for _, name := range wrongNames {
 		err = validator.ValidateName(name)
@@ -1,13 +1,23 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
-	Id    uuid.UUID `gorm:"primary_key;type:uuid;default:gen_random_uuid()"`
-	Login string    `gorm:"unique;not null;check:login <> ''"`
-	Pass  string    `gorm:"not null;check:pass <> ''"`
+	Id             uuid.UUID `gorm:"primary_key;type:uuid;default:gen_random_uuid()"`
+	Login          string    `gorm:"unique;not null;check:login <> ''"`
+	Pass           string    `gorm:"not null;check:pass <> ''"`
+	Role           string    `gorm:"not null;default:'user'"`
+	SuspendedUntil *time.Time
 }
 
 func New(login string, pass string) (*User, error) {
@@ -15,7 +25,16 @@ func New(login string, pass string) (*User, error) {
 		Id:    uuid.New(),
 		Login: login,
 		Pass:  pass,
+		Role:  RoleUser,
 	}
 
 	return &user, nil
 }
+
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.SuspendedUntil != nil && u.SuspendedUntil.After(now)
+}
+
+func (u *User) CanModerate() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 )
@@ -9,7 +11,15 @@ type Config struct {
 	Auth     AuthConfig
 	UserMgmt UserMgmtConfig
 	App      AppConfig
+	Grpc     GrpcConfig
 	Database DatabaseConfig
+	Redis    RedisConfig
+}
+
+type GrpcConfig struct {
+	// Services allowed to call the service RPCs, as name:token pairs
+	// separated by commas.
+	ServiceTokens map[string]string `env:"SERVICE_TOKENS"`
 }
 
 type AuthConfig struct {
@@ -23,8 +33,19 @@ type UserMgmtConfig struct {
 }
 
 type AppConfig struct {
-	HttpInnerPort int `env:"APP_HTTP_INNER_PORT"`
-	GrpcInnerPort int `env:"APP_GRPC_INNER_PORT"`
+	HttpInnerPort     int           `env:"APP_HTTP_INNER_PORT"`
+	GrpcInnerPort     int           `env:"APP_GRPC_INNER_PORT"`
+	MessageEditWindow time.Duration `env:"MESSAGE_EDIT_WINDOW" env-default:"48h"`
+	SearchLanguage    string        `env:"SEARCH_LANGUAGE" env-default:"simple"`
+	RestrictionSweep  time.Duration `env:"RESTRICTION_SWEEP_INTERVAL" env-default:"1m"`
+	PollSweep         time.Duration `env:"POLL_SWEEP_INTERVAL" env-default:"30s"`
+	// Zero means no limit.
+	GroupMemberLimit      int `env:"GROUP_MEMBER_LIMIT" env-default:"20"`
+	SupergroupMemberLimit int `env:"SUPERGROUP_MEMBER_LIMIT" env-default:"200000"`
+	ChannelMemberLimit    int `env:"CHANNEL_MEMBER_LIMIT" env-default:"0"`
+	// Browser origins allowed to open the websocket gateway besides the
+	// gateway's own host, separated by commas.
+	AllowedOrigins []string `env:"WS_ALLOWED_ORIGINS" env-separator:","`
 }
 
 type DatabaseConfig struct {
@@ -36,6 +57,13 @@ type DatabaseConfig struct {
 	SslMode      string `env:"DB_SSL_MODE"`
 }
 
+type RedisConfig struct {
+	Db        int    `env:"REDIS_DB"`
+	Password  string `env:"REDIS_PASSWORD"`
+	Host      string `env:"REDIS_HOST"`
+	InnerPort int    `env:"REDIS_INNER_PORT"`
+}
+
 func MustLoad() *Config {
 	err := godotenv.Load()
 	if err != nil {
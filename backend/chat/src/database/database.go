@@ -38,11 +38,60 @@ func Init(cfg *config.Config) {
 		panic(err)
 	}
 
-	db.AutoMigrate(&models.Chat{}, &models.UserChat{})
+	err = Migrate(db)
+	if err != nil {
+		slog.Error("Error has occured while migrating DB", "err", err)
+		panic(err)
+	}
 	DB = db
 	slog.Info("Connected to DB")
 }
 
+func Migrate(db *gorm.DB) error {
+	err := db.AutoMigrate(&models.Chat{}, &models.UserChat{}, &models.Message{}, &models.MessageEdit{}, &models.MessageDeletion{}, &models.ThreadFollower{}, &models.Reaction{}, &models.InviteLink{}, &models.InviteJoin{}, &models.JoinRequest{}, &models.ChatRestriction{}, &models.PinnedMessage{}, &models.Poll{}, &models.PollVote{}).Error
+	if err != nil {
+		return err
+	}
+	err = db.Exec("ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_vector tsvector").Error
+	if err != nil {
+		return err
+	}
+	err = db.Exec("CREATE INDEX IF NOT EXISTS idx_messages_search_vector ON messages USING GIN (search_vector)").Error
+	if err != nil {
+		return err
+	}
+	return migrateUniqueMemberships(db)
+}
+
+// Memberships are soft deleted, so only live rows have to be unique.
+// Duplicates left by racing joins are retired once, together with building
+// the index.
+func migrateUniqueMemberships(db *gorm.DB) error {
+	var exists bool
+	err := db.Raw("SELECT to_regclass('idx_user_chats_chat_user') IS NOT NULL").Row().Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	err = tx.Exec(`UPDATE user_chats SET deleted_at = now()
+		WHERE deleted_at IS NULL AND id NOT IN (
+			SELECT min(id) FROM user_chats WHERE deleted_at IS NULL GROUP BY chat_id, user_id
+		)`).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Exec("CREATE UNIQUE INDEX idx_user_chats_chat_user ON user_chats (chat_id, user_id) WHERE deleted_at IS NULL").Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
 func Close() {
 	slog.Info("Disconneting from DB")
 	DB.Close()
@@ -24,6 +24,12 @@ const _ = grpc.SupportPackageIsVersion7
 type UserMgmtClient interface {
 	AddUser(ctx context.Context, in *AddUserRequest, opts ...grpc.CallOption) (*UserResponse, error)
 	GetAllUsers(ctx context.Context, in *GetAllUsersRequest, opts ...grpc.CallOption) (*GetAllUsersResponse, error)
+	GetUsersByIds(ctx context.Context, in *GetUsersByIdsRequest, opts ...grpc.CallOption) (*GetAllUsersResponse, error)
+	SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*GetAllUsersResponse, error)
+	GetUserSettings(ctx context.Context, in *GetUserSettingsRequest, opts ...grpc.CallOption) (*UserSettingsResponse, error)
+	GetChatNotificationSettings(ctx context.Context, in *GetChatNotificationSettingsRequest, opts ...grpc.CallOption) (*GetChatNotificationSettingsResponse, error)
+	ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error)
+	ApplyModerationAction(ctx context.Context, in *ModerationActionRequest, opts ...grpc.CallOption) (*ModerationActionResponse, error)
 }
 
 type userMgmtClient struct {
@@ -52,12 +58,72 @@ func (c *userMgmtClient) GetAllUsers(ctx context.Context, in *GetAllUsersRequest
 	return out, nil
 }
 
+func (c *userMgmtClient) GetUsersByIds(ctx context.Context, in *GetUsersByIdsRequest, opts ...grpc.CallOption) (*GetAllUsersResponse, error) {
+	out := new(GetAllUsersResponse)
+	err := c.cc.Invoke(ctx, "/user_mgmt.UserMgmt/GetUsersByIds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userMgmtClient) SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*GetAllUsersResponse, error) {
+	out := new(GetAllUsersResponse)
+	err := c.cc.Invoke(ctx, "/user_mgmt.UserMgmt/SearchUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userMgmtClient) GetUserSettings(ctx context.Context, in *GetUserSettingsRequest, opts ...grpc.CallOption) (*UserSettingsResponse, error) {
+	out := new(UserSettingsResponse)
+	err := c.cc.Invoke(ctx, "/user_mgmt.UserMgmt/GetUserSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userMgmtClient) GetChatNotificationSettings(ctx context.Context, in *GetChatNotificationSettingsRequest, opts ...grpc.CallOption) (*GetChatNotificationSettingsResponse, error) {
+	out := new(GetChatNotificationSettingsResponse)
+	err := c.cc.Invoke(ctx, "/user_mgmt.UserMgmt/GetChatNotificationSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userMgmtClient) ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error) {
+	out := new(ListReportsResponse)
+	err := c.cc.Invoke(ctx, "/user_mgmt.UserMgmt/ListReports", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userMgmtClient) ApplyModerationAction(ctx context.Context, in *ModerationActionRequest, opts ...grpc.CallOption) (*ModerationActionResponse, error) {
+	out := new(ModerationActionResponse)
+	err := c.cc.Invoke(ctx, "/user_mgmt.UserMgmt/ApplyModerationAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserMgmtServer is the server API for UserMgmt service.
 // All implementations must embed UnimplementedUserMgmtServer
 // for forward compatibility
 type UserMgmtServer interface {
 	AddUser(context.Context, *AddUserRequest) (*UserResponse, error)
 	GetAllUsers(context.Context, *GetAllUsersRequest) (*GetAllUsersResponse, error)
+	GetUsersByIds(context.Context, *GetUsersByIdsRequest) (*GetAllUsersResponse, error)
+	SearchUsers(context.Context, *SearchUsersRequest) (*GetAllUsersResponse, error)
+	GetUserSettings(context.Context, *GetUserSettingsRequest) (*UserSettingsResponse, error)
+	GetChatNotificationSettings(context.Context, *GetChatNotificationSettingsRequest) (*GetChatNotificationSettingsResponse, error)
+	ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error)
+	ApplyModerationAction(context.Context, *ModerationActionRequest) (*ModerationActionResponse, error)
 	mustEmbedUnimplementedUserMgmtServer()
 }
 
@@ -71,6 +137,24 @@ func (UnimplementedUserMgmtServer) AddUser(context.Context, *AddUserRequest) (*U
 func (UnimplementedUserMgmtServer) GetAllUsers(context.Context, *GetAllUsersRequest) (*GetAllUsersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllUsers not implemented")
 }
+func (UnimplementedUserMgmtServer) GetUsersByIds(context.Context, *GetUsersByIdsRequest) (*GetAllUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsersByIds not implemented")
+}
+func (UnimplementedUserMgmtServer) SearchUsers(context.Context, *SearchUsersRequest) (*GetAllUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchUsers not implemented")
+}
+func (UnimplementedUserMgmtServer) GetUserSettings(context.Context, *GetUserSettingsRequest) (*UserSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserSettings not implemented")
+}
+func (UnimplementedUserMgmtServer) GetChatNotificationSettings(context.Context, *GetChatNotificationSettingsRequest) (*GetChatNotificationSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChatNotificationSettings not implemented")
+}
+func (UnimplementedUserMgmtServer) ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReports not implemented")
+}
+func (UnimplementedUserMgmtServer) ApplyModerationAction(context.Context, *ModerationActionRequest) (*ModerationActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyModerationAction not implemented")
+}
 func (UnimplementedUserMgmtServer) mustEmbedUnimplementedUserMgmtServer() {}
 
 // UnsafeUserMgmtServer may be embedded to opt out of forward compatibility for this service.
@@ -120,6 +204,114 @@ func _UserMgmt_GetAllUsers_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserMgmt_GetUsersByIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsersByIdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserMgmtServer).GetUsersByIds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user_mgmt.UserMgmt/GetUsersByIds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserMgmtServer).GetUsersByIds(ctx, req.(*GetUsersByIdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserMgmt_SearchUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserMgmtServer).SearchUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user_mgmt.UserMgmt/SearchUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserMgmtServer).SearchUsers(ctx, req.(*SearchUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserMgmt_GetUserSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserMgmtServer).GetUserSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user_mgmt.UserMgmt/GetUserSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserMgmtServer).GetUserSettings(ctx, req.(*GetUserSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserMgmt_GetChatNotificationSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChatNotificationSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserMgmtServer).GetChatNotificationSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user_mgmt.UserMgmt/GetChatNotificationSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserMgmtServer).GetChatNotificationSettings(ctx, req.(*GetChatNotificationSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserMgmt_ListReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserMgmtServer).ListReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user_mgmt.UserMgmt/ListReports",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserMgmtServer).ListReports(ctx, req.(*ListReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserMgmt_ApplyModerationAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerationActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserMgmtServer).ApplyModerationAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user_mgmt.UserMgmt/ApplyModerationAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserMgmtServer).ApplyModerationAction(ctx, req.(*ModerationActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserMgmt_ServiceDesc is the grpc.ServiceDesc for UserMgmt service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -135,6 +327,30 @@ var UserMgmt_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAllUsers",
 			Handler:    _UserMgmt_GetAllUsers_Handler,
 		},
+		{
+			MethodName: "GetUsersByIds",
+			Handler:    _UserMgmt_GetUsersByIds_Handler,
+		},
+		{
+			MethodName: "SearchUsers",
+			Handler:    _UserMgmt_SearchUsers_Handler,
+		},
+		{
+			MethodName: "GetUserSettings",
+			Handler:    _UserMgmt_GetUserSettings_Handler,
+		},
+		{
+			MethodName: "GetChatNotificationSettings",
+			Handler:    _UserMgmt_GetChatNotificationSettings_Handler,
+		},
+		{
+			MethodName: "ListReports",
+			Handler:    _UserMgmt_ListReports_Handler,
+		},
+		{
+			MethodName: "ApplyModerationAction",
+			Handler:    _UserMgmt_ApplyModerationAction_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user_mgmt/user_mgmt.proto",
@@ -11,8 +11,10 @@ import (
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/gen/go/auth"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/gen/go/user_mgmt"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type AuthGRPCClient struct {
@@ -33,7 +35,11 @@ func NewAuthClient(cfg *config.Config) *AuthGRPCClient {
 func (authClient *AuthGRPCClient) PerformAuthorize(ctx context.Context, r *http.Request) (*auth.AuthorizeResponse, error) {
 	var accessToken string
 	if r == nil {
-		accessToken = metadata.ValueFromIncomingContext(ctx, "authorization")[0]
+		values := metadata.ValueFromIncomingContext(ctx, "authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "authorization is missing")
+		}
+		accessToken = values[0]
 	} else {
 		ctx = r.Context()
 		authHeader := r.Header.Get("Authorization")
@@ -63,3 +69,15 @@ func (c *UserMgmtGRPCClient) PerformGetAllUsers(ctx context.Context, r *http.Req
 		return c.UserMgmtClient.GetAllUsers(ctx, &user_mgmt.GetAllUsersRequest{})
 	}
 }
+
+func (c *UserMgmtGRPCClient) PerformGetUsersByIds(ctx context.Context, userIds []string) (*user_mgmt.GetAllUsersResponse, error) {
+	return c.UserMgmtClient.GetUsersByIds(ctx, &user_mgmt.GetUsersByIdsRequest{UserIds: userIds})
+}
+
+func (c *UserMgmtGRPCClient) PerformSearchUsers(ctx context.Context, query string, userIds []string, limit int) (*user_mgmt.GetAllUsersResponse, error) {
+	return c.UserMgmtClient.SearchUsers(ctx, &user_mgmt.SearchUsersRequest{Query: query, UserIds: userIds, Limit: int32(limit)})
+}
+
+func (c *UserMgmtGRPCClient) PerformGetChatNotificationSettings(ctx context.Context, userId string, chatIds []string) (*user_mgmt.GetChatNotificationSettingsResponse, error) {
+	return c.UserMgmtClient.GetChatNotificationSettings(ctx, &user_mgmt.GetChatNotificationSettingsRequest{UserId: userId, ChatIds: chatIds})
+}
@@ -6,7 +6,6 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
-	"slices"
 	"strings"
 
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/client"
@@ -53,11 +52,18 @@ func (c *ChatManagementController) GetAllAvailableChatsHandler(w http.ResponseWr
 	availableUsers := make([]*dto.AvailableUsersResponse, 0)
 	for _, user := range users.Users {
 		availableUsers = append(availableUsers, &dto.AvailableUsersResponse{
-			Id:          user.UserId,
-			Name:        user.Name,
-			UrlTag:      user.UrlTag,
-			Description: user.Description,
-			ProfilePic:  user.ProfilePic,
+			Id:              user.UserId,
+			Name:            user.Name,
+			UrlTag:          user.UrlTag,
+			Description:     user.Description,
+			ProfilePic:      user.ProfilePic,
+			StatusEmoji:     user.StatusEmoji,
+			StatusText:      user.StatusText,
+			StatusExpiresAt: user.StatusExpiresAt,
+			Birthday:        user.Birthday,
+			Pronouns:        user.Pronouns,
+			Links:           user.Links,
+			Timezone:        user.Timezone,
 		})
 	}
 	chats, err := c.service.GetAllChatsForUser(userId)
@@ -66,14 +72,59 @@ func (c *ChatManagementController) GetAllAvailableChatsHandler(w http.ResponseWr
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	chatIds := make([]string, 0, len(chats))
+	chatUuids := make([]uuid.UUID, 0, len(chats))
+	for _, chat := range chats {
+		chatIds = append(chatIds, chat.Id.String())
+		chatUuids = append(chatUuids, chat.Id)
+	}
+	muted := make(map[string]bool)
+	if len(chatIds) > 0 {
+		notificationSettings, err := c.userMgmtClient.PerformGetChatNotificationSettings(r.Context(), authResp.UserId, chatIds)
+		if err != nil {
+			slog.Error("Failed to get chat notification settings", "error", err.Error())
+		} else {
+			for _, settings := range notificationSettings.Settings {
+				muted[settings.ChatId] = settings.Muted
+			}
+		}
+	}
+	threadsCounts, err := c.service.CountChatThreads(chatUuids)
+	if err != nil {
+		slog.Error("Failed to count threads", "error", err.Error())
+	}
+	unreadCounts, lastMessages, err := c.service.GetChatReadStates(chatUuids, userId)
+	if err != nil {
+		slog.Error("Failed to get chat read state", "error", err.Error())
+	}
+	var directChatIds []uuid.UUID
+	for _, chat := range chats {
+		if chat.IsDirect {
+			directChatIds = append(directChatIds, chat.Id)
+		}
+	}
+	peers, err := c.service.GetDirectPeers(directChatIds, userId)
+	if err != nil {
+		slog.Error("Failed to get direct chat peers", "error", err.Error())
+	}
 	availableChats := make([]*dto.AvailableChatsResponse, 0)
 	for _, chat := range chats {
+		peerId := ""
+		if peer, ok := peers[chat.Id]; ok {
+			peerId = peer.String()
+		}
 		availableChats = append(availableChats, &dto.AvailableChatsResponse{
-			Id:          chat.Id.String(),
-			Name:        chat.Name,
-			IsChannel:   chat.IsChannel,
-			Description: chat.Description,
-			ProfilePic:  chat.ProfilePic,
+			Id:           chat.Id.String(),
+			Name:         chat.Name,
+			IsChannel:    chat.IsChannel,
+			IsDirect:     chat.IsDirect,
+			PeerId:       peerId,
+			Description:  chat.Description,
+			ProfilePic:   chat.ProfilePic,
+			Muted:        muted[chat.Id.String()],
+			ThreadsCount: threadsCounts[chat.Id],
+			UnreadCount:  unreadCounts[chat.Id],
+			LastMessage:  lastMessages[chat.Id],
 		})
 	}
 
@@ -110,6 +161,12 @@ func (c *ChatManagementController) CreateChatHandler(w http.ResponseWriter, r *h
 		return
 	}
 
+	creatorId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if len(chatReq.ParticipantsIds) < 2 {
 		http.Error(w, "Not enough participants", http.StatusBadRequest)
 		return
@@ -121,7 +178,7 @@ func (c *ChatManagementController) CreateChatHandler(w http.ResponseWriter, r *h
 		return
 	}
 
-	chat, err := c.service.CreateChat(&chatReq)
+	chat, err := c.service.CreateChat(creatorId, &chatReq)
 	if err != nil {
 		slog.Error("Failed to create chat", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -161,15 +218,14 @@ func (c *ChatManagementController) DeleteChatHandler(w http.ResponseWriter, r *h
 		return
 	}
 
-	chat, err := c.service.GetChat(chatId)
+	actorId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
-		slog.Error("c.service.GetChat() returned error", "error", err.Error())
-		http.Error(w, err.Error(), http.StatusNotFound)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-
-	if chat.Chat.CreatorId.String() != authResp.UserId {
-		http.Error(w, "You are not the creator of the chat", http.StatusForbidden)
+	err = c.service.Authorize(chatId, actorId, service.ActionDeleteChat)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
 
@@ -179,7 +235,7 @@ func (c *ChatManagementController) DeleteChatHandler(w http.ResponseWriter, r *h
 		return
 	}
 
-	chat, err = c.service.GetChat(chatId)
+	chat, err := c.service.GetChat(chatId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -217,13 +273,19 @@ func (c *ChatManagementController) GetChatHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	chat, err := c.service.GetChat(chatId)
+	userId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if !(slices.Contains(chat.Readers, authResp.UserId) || slices.Contains(chat.Users, authResp.UserId) || slices.Contains(chat.Admins, authResp.UserId)) {
-		http.Error(w, "permission denied", http.StatusForbidden)
+	err = c.service.Authorize(chatId, userId, service.ActionViewChat)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	chat, err := c.service.GetChat(chatId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	chatResp, err := json.Marshal(chat)
@@ -252,30 +314,32 @@ func (c *ChatManagementController) UpdateChatHandler(w http.ResponseWriter, r *h
 		return
 	}
 
-	chat, err := c.service.GetChat(chatReq.ChatId)
+	actorId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
-		slog.Error("c.service.GetChat() returned error", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if !(slices.Contains(chat.Admins, authResp.UserId) || chat.Chat.CreatorId.String() == authResp.UserId) {
-		http.Error(w, "permission denied", http.StatusForbidden)
-		return
-	}
 
 	err = validator.ValidateChatName(chatReq.Name)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if chatReq.AllowedReactions != nil {
+		err = validator.ValidateAllowedReactions(*chatReq.AllowedReactions)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
-	err = c.service.UpdateChat(&chatReq)
+	err = c.service.UpdateChat(actorId, &chatReq)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
 
-	chat, err = c.service.GetChat(chatReq.ChatId)
+	chat, err := c.service.GetChat(chatReq.ChatId)
 	if err != nil {
 		slog.Error("c.service.GetChat() returned error", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -307,24 +371,19 @@ func (c *ChatManagementController) DeleteUsersInChatHandler(w http.ResponseWrite
 		return
 	}
 
-	chat, err := c.service.GetChat(req.ChatId)
+	actorId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
-		slog.Error("c.service.GetChat() returned error", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if !(slices.Contains(chat.Admins, authResp.UserId) || chat.Chat.CreatorId.String() == authResp.UserId) {
-		http.Error(w, "permission denied", http.StatusForbidden)
-		return
-	}
 
-	err = c.service.DeleteUsers(req.ChatId, req.UserIds)
+	err = c.service.DeleteUsers(req.ChatId, actorId, req.UserIds)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
 
-	chat, err = c.service.GetChat(req.ChatId)
+	chat, err := c.service.GetChat(req.ChatId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -355,24 +414,19 @@ func (c *ChatManagementController) AddUsersInChatHandler(w http.ResponseWriter,
 		return
 	}
 
-	chat, err := c.service.GetChat(req.ChatId)
+	actorId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
-		slog.Error("c.service.GetChat() returned error", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if !(slices.Contains(chat.Admins, authResp.UserId) || chat.Chat.CreatorId.String() == authResp.UserId) {
-		http.Error(w, "permission denied", http.StatusForbidden)
-		return
-	}
 
-	err = c.service.AddUsers(req.ChatId, req.UserIds)
+	err = c.service.AddUsers(req.ChatId, actorId, req.UserIds)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
 
-	chat, err = c.service.GetChat(req.ChatId)
+	chat, err := c.service.GetChat(req.ChatId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -403,24 +457,19 @@ func (c *ChatManagementController) MakeReadersUsersInChatHandler(w http.Response
 		return
 	}
 
-	chat, err := c.service.GetChat(req.ChatId)
+	actorId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
-		slog.Error("c.service.GetChat() returned error", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if !(slices.Contains(chat.Admins, authResp.UserId) || chat.Chat.CreatorId.String() == authResp.UserId) {
-		http.Error(w, "permission denied", http.StatusForbidden)
-		return
-	}
 
-	err = c.service.MakeReadersUsers(req.ChatId, req.ReadersIds)
+	err = c.service.MakeReadersUsers(req.ChatId, actorId, req.ReadersIds)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
 
-	chat, err = c.service.GetChat(req.ChatId)
+	chat, err := c.service.GetChat(req.ChatId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -451,24 +500,19 @@ func (c *ChatManagementController) MakeUsersReadersInChatHandler(w http.Response
 		return
 	}
 
-	chat, err := c.service.GetChat(req.ChatId)
+	actorId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
-		slog.Error("c.service.GetChat() returned error", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if !(slices.Contains(chat.Admins, authResp.UserId) || chat.Chat.CreatorId.String() == authResp.UserId) {
-		http.Error(w, "permission denied", http.StatusForbidden)
-		return
-	}
 
-	err = c.service.MakeUsersReaders(req.ChatId, req.ReadersIds)
+	err = c.service.MakeUsersReaders(req.ChatId, actorId, req.ReadersIds)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
 
-	chat, err = c.service.GetChat(req.ChatId)
+	chat, err := c.service.GetChat(req.ChatId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -499,24 +543,19 @@ func (c *ChatManagementController) AddAdminsInChatHandler(w http.ResponseWriter,
 		return
 	}
 
-	chat, err := c.service.GetChat(req.ChatId)
+	actorId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
-		slog.Error("c.service.GetChat() returned error", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if chat.Chat.CreatorId.String() != authResp.UserId {
-		http.Error(w, "permission denied", http.StatusForbidden)
-		return
-	}
 
-	err = c.service.AddAdmin(req.ChatId, req.AdminsIds)
+	err = c.service.AddAdmin(req.ChatId, actorId, req.AdminsIds, req.Rights)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
 
-	chat, err = c.service.GetChat(req.ChatId)
+	chat, err := c.service.GetChat(req.ChatId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -547,24 +586,19 @@ func (c *ChatManagementController) DeleteAdminsInChatHandler(w http.ResponseWrit
 		return
 	}
 
-	chat, err := c.service.GetChat(req.ChatId)
+	actorId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
-		slog.Error("c.service.GetChat() returned error", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if chat.Chat.CreatorId.String() != authResp.UserId {
-		http.Error(w, "permission denied", http.StatusForbidden)
-		return
-	}
 
-	err = c.service.DeleteAdmin(req.ChatId, req.AdminsIds)
+	err = c.service.DeleteAdmin(req.ChatId, actorId, req.AdminsIds)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
 
-	chat, err = c.service.GetChat(req.ChatId)
+	chat, err := c.service.GetChat(req.ChatId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -594,12 +628,12 @@ func (c *ChatManagementController) JoinChatHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	chat, err := c.service.JoinChat(joinLink, userId)
+	joinResp, err := c.service.JoinChat(joinLink, userId)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), messageErrorStatus(err))
 		return
 	}
-	chatResp, err := json.Marshal(chat)
+	chatResp, err := json.Marshal(joinResp)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
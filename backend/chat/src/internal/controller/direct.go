@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/google/uuid"
+)
+
+func (c *ChatManagementController) GetOrCreateDirectChatHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.DirectChatRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	peers, err := c.userMgmtClient.PerformGetUsersByIds(r.Context(), []string{req.UserId.String()})
+	if err != nil {
+		slog.Error("Failed to get direct chat peer", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(peers.Users) == 0 {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+
+	chat, err := c.service.GetOrCreateDirectChat(userId, req.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	chatResp, err := json.Marshal(chat)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(chatResp)
+}
@@ -0,0 +1,294 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/validator"
+	"github.com/google/uuid"
+)
+
+func (c *ChatManagementController) CreateInviteLinkHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateInviteLinkRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateInviteLink(req.Name, req.MaxUses, req.ExpiresAt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	link, err := c.service.CreateInviteLink(userId, &req)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	linkResp, err := json.Marshal(link)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.WriteHeader(http.StatusCreated)
+	w.Write(linkResp)
+}
+
+func (c *ChatManagementController) GetInviteLinksHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chatId, err := uuid.Parse(params.Get("chatId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	links, err := c.service.GetInviteLinks(chatId, userId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	linksResp, err := json.Marshal(links)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(linksResp)
+}
+
+func (c *ChatManagementController) RevokeInviteLinkHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.InviteLinkRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = c.service.RevokeInviteLink(req.ChatId, req.LinkId, userId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *ChatManagementController) RotateInviteLinkHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.InviteLinkRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	link, err := c.service.RotateInviteLink(req.ChatId, req.LinkId, userId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	linkResp, err := json.Marshal(link)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(linkResp)
+}
+
+func (c *ChatManagementController) GetInviteJoinsHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chatId, err := uuid.Parse(params.Get("chatId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var linkId *uuid.UUID
+	if params.Has("linkId") {
+		id, err := uuid.Parse(params.Get("linkId"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		linkId = &id
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	joins, err := c.service.GetInviteJoins(chatId, userId, linkId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	joinsResp, err := json.Marshal(joins)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(joinsResp)
+}
+
+func (c *ChatManagementController) GetJoinRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chatId, err := uuid.Parse(params.Get("chatId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	requests, err := c.service.GetJoinRequests(chatId, userId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	requestsResp, err := json.Marshal(requests)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(requestsResp)
+}
+
+func (c *ChatManagementController) ApproveJoinRequestHandler(w http.ResponseWriter, r *http.Request) {
+	c.decideJoinRequest(w, r, true)
+}
+
+func (c *ChatManagementController) RejectJoinRequestHandler(w http.ResponseWriter, r *http.Request) {
+	c.decideJoinRequest(w, r, false)
+}
+
+func (c *ChatManagementController) decideJoinRequest(w http.ResponseWriter, r *http.Request, approve bool) {
+	var req dto.JoinRequestDecision
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request, err := c.service.DecideJoinRequest(req.ChatId, req.RequestId, userId, approve)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	requestResp, err := json.Marshal(request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(requestResp)
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/gen/go/user_mgmt"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/google/uuid"
+)
+
+func (c *ChatManagementController) GetMembersHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chatId, err := uuid.Parse(params.Get("chatId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := &dto.MembersQuery{Cursor: params.Get("cursor"), Role: params.Get("role")}
+	if params.Has("limit") {
+		query.Limit, err = strconv.Atoi(params.Get("limit"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Names live in user_mgmt, so a search narrows the page to matching ids.
+	search := strings.TrimSpace(params.Get("query"))
+	if search != "" {
+		query.UserIds, err = c.service.SearchMembers(chatId, userId, func(userIds []uuid.UUID, limit int) ([]uuid.UUID, error) {
+			return c.searchUsers(r.Context(), search, userIds, limit)
+		})
+		if err != nil {
+			slog.Error("Failed to search members", "error", err.Error())
+			http.Error(w, err.Error(), messageErrorStatus(err))
+			return
+		}
+	}
+
+	page, err := c.service.GetMembers(chatId, userId, query)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	err = c.fillMemberProfiles(r.Context(), page.Members)
+	if err != nil {
+		slog.Error("Failed to get member profiles", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pageResp, err := json.Marshal(page)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(pageResp)
+}
+
+func (c *ChatManagementController) UpgradeToSupergroupHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.ChatIdRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chat, err := c.service.UpgradeToSupergroup(userId, req.ChatId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	chatResp, err := json.Marshal(chat)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(chatResp)
+}
+
+// Only the members on the page are looked up.
+func (c *ChatManagementController) fillMemberProfiles(ctx context.Context, members []dto.ChatMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+	userIds := make([]string, 0, len(members))
+	for _, member := range members {
+		userIds = append(userIds, member.UserId.String())
+	}
+	users, err := c.userMgmtClient.PerformGetUsersByIds(ctx, userIds)
+	if err != nil {
+		return err
+	}
+	usersById := make(map[string]*user_mgmt.UserResponse, len(users.Users))
+	for _, user := range users.Users {
+		usersById[user.UserId] = user
+	}
+	for i := range members {
+		if user, ok := usersById[members[i].UserId.String()]; ok {
+			members[i].Name = user.Name
+			members[i].ProfilePic = user.ProfilePic
+		}
+	}
+	return nil
+}
+
+func (c *ChatManagementController) searchUsers(ctx context.Context, search string, userIds []uuid.UUID, limit int) ([]uuid.UUID, error) {
+	rawIds := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		rawIds = append(rawIds, userId.String())
+	}
+	users, err := c.userMgmtClient.PerformSearchUsers(ctx, search, rawIds, limit)
+	if err != nil {
+		return nil, err
+	}
+	found := make([]uuid.UUID, 0, len(users.Users))
+	for _, user := range users.Users {
+		id, err := uuid.Parse(user.UserId)
+		if err == nil {
+			found = append(found, id)
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/service"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/validator"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+func (c *ChatManagementController) SendMessageHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.SendMessageRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	senderId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = validator.ValidateMessage(req.Text, req.HasAttachments)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	message, err := c.service.SendMessage(req.ChatId, senderId, &req)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	messageResp, err := json.Marshal(message)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(messageResp)
+}
+
+func (c *ChatManagementController) GetMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chatId, err := uuid.Parse(params.Get("chatId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := 0
+	if params.Has("limit") {
+		limit, err = strconv.Atoi(params.Get("limit"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := c.service.GetMessages(chatId, userId, params.Get("cursor"), limit)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	pageResp, err := json.Marshal(page)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(pageResp)
+}
+
+func (c *ChatManagementController) EditMessageHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.EditMessageRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	editorId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = validator.ValidateMessage(req.Text, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	message, err := c.service.EditMessage(editorId, &req)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	messageResp, err := json.Marshal(message)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(messageResp)
+}
+
+func (c *ChatManagementController) DeleteMessageHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteMessageRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = c.service.DeleteMessage(userId, &req)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *ChatManagementController) GetMessageEditsHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chatId, err := uuid.Parse(params.Get("chatId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	messageId, err := uuid.Parse(params.Get("messageId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	edits, err := c.service.GetMessageEdits(chatId, messageId, userId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	editsResp, err := json.Marshal(edits)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(editsResp)
+}
+
+func messageErrorStatus(err error) int {
+	switch {
+	case gorm.IsRecordNotFoundError(err), errors.Is(err, service.ErrNoPendingTransfer):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrNotMember), errors.Is(err, service.ErrReadOnly), errors.Is(err, service.ErrChannelPostDeny),
+		errors.Is(err, service.ErrNotSender), errors.Is(err, service.ErrDeleteDenied), errors.Is(err, service.ErrNotChatAdmin),
+		errors.Is(err, service.ErrReadersCannotReact), errors.Is(err, service.ErrReactionNotAllowed),
+		errors.Is(err, service.ErrDirectChatBlocked), errors.Is(err, service.ErrPermissionDenied), errors.Is(err, service.ErrMissingAdminRight),
+		errors.Is(err, service.ErrNotChatCreator), errors.Is(err, service.ErrOutranked), errors.Is(err, service.ErrBanned),
+		errors.Is(err, service.ErrMuteAdmin), errors.Is(err, service.ErrReadersCannotVote),
+		errors.Is(err, service.ErrAnonymousPoll), errors.Is(err, service.ErrPollCloseDenied), errors.Is(err, service.ErrReadersCannotEmit):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrInviteRevoked), errors.Is(err, service.ErrInviteExpired), errors.Is(err, service.ErrInviteExhausted):
+		return http.StatusGone
+	case errors.Is(err, service.ErrEditWindowExpired), errors.Is(err, service.ErrJoinRequestDecided), errors.Is(err, service.ErrChatFull),
+		errors.Is(err, service.ErrAlreadyReader), errors.Is(err, service.ErrAlreadySupergroup), errors.Is(err, service.ErrPollClosed),
+		errors.Is(err, service.ErrAlreadyVoted):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrSignalRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrNotThreadRoot), errors.Is(err, service.ErrUnknownSignal),
+		errors.Is(err, service.ErrEmptySearchQuery), errors.Is(err, service.ErrDirectWithSelf), errors.Is(err, service.ErrDirectChat),
+		errors.Is(err, service.ErrRestrictSelf), errors.Is(err, service.ErrChannelMute), errors.Is(err, service.ErrTransferToSelf),
+		errors.Is(err, service.ErrChannelUpgrade), errors.Is(err, service.ErrInvalidMemberRole), errors.Is(err, service.ErrServiceMessage),
+		errors.Is(err, service.ErrNotPoll), errors.Is(err, service.ErrPollEdit), errors.Is(err, service.ErrInvalidPollOption),
+		errors.Is(err, service.ErrSingleChoice), errors.Is(err, service.ErrQuizRetract):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
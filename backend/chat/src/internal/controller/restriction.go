@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/validator"
+	"github.com/google/uuid"
+)
+
+func (c *ChatManagementController) BanMemberHandler(w http.ResponseWriter, r *http.Request) {
+	c.restrictMember(w, r, models.RestrictionBan)
+}
+
+func (c *ChatManagementController) MuteMemberHandler(w http.ResponseWriter, r *http.Request) {
+	c.restrictMember(w, r, models.RestrictionMute)
+}
+
+func (c *ChatManagementController) restrictMember(w http.ResponseWriter, r *http.Request, kind string) {
+	var req dto.RestrictMemberRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateRestriction(req.Until, req.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	actorId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var restriction *models.ChatRestriction
+	if kind == models.RestrictionBan {
+		restriction, err = c.service.BanMember(actorId, &req)
+	} else {
+		restriction, err = c.service.MuteMember(actorId, &req)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	restrictionResp, err := json.Marshal(restriction)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(restrictionResp)
+}
+
+func (c *ChatManagementController) UnbanMemberHandler(w http.ResponseWriter, r *http.Request) {
+	c.liftRestriction(w, r, models.RestrictionBan)
+}
+
+func (c *ChatManagementController) UnmuteMemberHandler(w http.ResponseWriter, r *http.Request) {
+	c.liftRestriction(w, r, models.RestrictionMute)
+}
+
+func (c *ChatManagementController) liftRestriction(w http.ResponseWriter, r *http.Request, kind string) {
+	var req dto.LiftRestrictionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	actorId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if kind == models.RestrictionBan {
+		err = c.service.UnbanMember(actorId, &req)
+	} else {
+		err = c.service.UnmuteMember(actorId, &req)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *ChatManagementController) GetRestrictionsHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chatId, err := uuid.Parse(params.Get("chatId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	restrictions, err := c.service.GetRestrictions(chatId, userId)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	restrictionsResp, err := json.Marshal(restrictions)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(restrictionsResp)
+}
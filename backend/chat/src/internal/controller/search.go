@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/google/uuid"
+)
+
+func (c *ChatManagementController) SearchChatMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chatId, err := uuid.Parse(params.Get("chatId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.searchMessages(w, r, params, &chatId)
+}
+
+func (c *ChatManagementController) SearchMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.searchMessages(w, r, params, nil)
+}
+
+func (c *ChatManagementController) searchMessages(w http.ResponseWriter, r *http.Request, params url.Values, chatId *uuid.UUID) {
+	req, err := parseSearchParams(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results, err := c.service.SearchMessages(userId, chatId, req)
+	if err != nil {
+		http.Error(w, err.Error(), messageErrorStatus(err))
+		return
+	}
+	resultsResp, err := json.Marshal(results)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(resultsResp)
+}
+
+func parseSearchParams(params url.Values) (*dto.SearchMessagesRequest, error) {
+	req := &dto.SearchMessagesRequest{Query: params.Get("q"), Cursor: params.Get("cursor")}
+	if params.Has("senderId") {
+		senderId, err := uuid.Parse(params.Get("senderId"))
+		if err != nil {
+			return nil, err
+		}
+		req.SenderId = &senderId
+	}
+	if params.Has("from") {
+		from, err := time.Parse(time.RFC3339, params.Get("from"))
+		if err != nil {
+			return nil, err
+		}
+		req.From = &from
+	}
+	if params.Has("to") {
+		to, err := time.Parse(time.RFC3339, params.Get("to"))
+		if err != nil {
+			return nil, err
+		}
+		req.To = &to
+	}
+	if params.Has("hasAttachment") {
+		hasAttachment, err := strconv.ParseBool(params.Get("hasAttachment"))
+		if err != nil {
+			return nil, err
+		}
+		req.HasAttachment = &hasAttachment
+	}
+	if params.Has("limit") {
+		limit, err := strconv.Atoi(params.Get("limit"))
+		if err != nil {
+			return nil, err
+		}
+		req.Limit = limit
+	}
+	return req, nil
+}
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
 	"github.com/google/uuid"
 )
@@ -8,7 +10,6 @@ import (
 type CreateChatRequest struct {
 	Name            string      `json:"name"`
 	Description     string      `json:"description"`
-	CreatorId       uuid.UUID   `json:"creator_id"`
 	ParticipantsIds []uuid.UUID `json:"participants_ids"`
 	IsChannel       bool        `json:"is_channel"`
 }
@@ -18,6 +19,11 @@ type UpdateChatRequest struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	ProfilePic  string    `json:"profile_pic"`
+	// Left unchanged when omitted.
+	AllowedReactions  *[]string          `json:"allowed_reactions"`
+	ReadersCanReact   *bool              `json:"readers_can_react"`
+	ReadersCanVote    *bool              `json:"readers_can_vote"`
+	MemberPermissions *models.Permission `json:"member_permissions"`
 }
 
 type UpdateUsersRequest struct {
@@ -28,6 +34,8 @@ type UpdateUsersRequest struct {
 type UpdateAdminsRequest struct {
 	ChatId    uuid.UUID   `json:"chat_id"`
 	AdminsIds []uuid.UUID `json:"admins_ids"`
+	// Every right the caller holds when omitted.
+	Rights *models.AdminRight `json:"rights"`
 }
 
 type UpdateReadersRequest struct {
@@ -36,10 +44,14 @@ type UpdateReadersRequest struct {
 }
 
 type GetChatResponse struct {
-	Chat    *models.Chat
-	Users   []string
-	Admins  []string
-	Readers []string
+	Chat         *models.Chat
+	Users        []string
+	Admins       []string
+	Readers      []string
+	AdminRights  map[string]models.AdminRight
+	ThreadsCount int
+	MembersCount int
+	LatestPin    *Pin
 }
 
 type GetAllChatsResponse struct {
@@ -48,17 +60,348 @@ type GetAllChatsResponse struct {
 }
 
 type AvailableUsersResponse struct {
-	Id          string `json:"id"`
-	Name        string `json:"name"`
-	UrlTag      string `json:"url_tag"`
-	Description string `json:"description"`
-	ProfilePic  string `json:"profile_pic"`
+	Id              string   `json:"id"`
+	Name            string   `json:"name"`
+	UrlTag          string   `json:"url_tag"`
+	Description     string   `json:"description"`
+	ProfilePic      string   `json:"profile_pic"`
+	StatusEmoji     string   `json:"status_emoji"`
+	StatusText      string   `json:"status_text"`
+	StatusExpiresAt int64    `json:"status_expires_at"`
+	Birthday        string   `json:"birthday"`
+	Pronouns        string   `json:"pronouns"`
+	Links           []string `json:"links"`
+	Timezone        string   `json:"timezone"`
 }
 
 type AvailableChatsResponse struct {
-	Id          string `json:"id"`
-	Name        string `json:"name"`
-	IsChannel   bool   `json:"is_channel"`
-	Description string `json:"description"`
-	ProfilePic  string `json:"profile_pic"`
+	Id           string              `json:"id"`
+	Name         string              `json:"name"`
+	IsChannel    bool                `json:"is_channel"`
+	IsDirect     bool                `json:"is_direct"`
+	PeerId       string              `json:"peer_id,omitempty"`
+	Description  string              `json:"description"`
+	ProfilePic   string              `json:"profile_pic"`
+	Muted        bool                `json:"muted"`
+	ThreadsCount int                 `json:"threads_count"`
+	UnreadCount  int                 `json:"unread_count"`
+	LastMessage  *LastMessagePreview `json:"last_message"`
+}
+
+type LastMessagePreview struct {
+	Id             uuid.UUID `json:"id"`
+	SenderId       uuid.UUID `json:"sender_id"`
+	Text           string    `json:"text"`
+	HasAttachments bool      `json:"has_attachments"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type SendMessageRequest struct {
+	ChatId       uuid.UUID  `json:"chat_id"`
+	Text         string     `json:"text"`
+	ReplyToId    *uuid.UUID `json:"reply_to_id"`
+	ThreadRootId *uuid.UUID `json:"thread_root_id"`
+	// Files are uploaded to media_handler with the returned message id and
+	// attached once media_handler reports them.
+	HasAttachments bool `json:"has_attachments"`
+}
+
+type MessagesPageResponse struct {
+	Messages   []models.Message `json:"messages"`
+	NextCursor string           `json:"next_cursor"`
+}
+
+// UserId is the uploader, only the message's sender may attach files to it.
+type MessageIdXFileId struct {
+	MessageId uuid.UUID `json:"messageId"`
+	FileId    uuid.UUID `json:"fileId"`
+	UserId    uuid.UUID `json:"userId"`
+}
+
+const (
+	EventMessageCreated = "message_created"
+	EventMessageEdited  = "message_edited"
+	EventMessageDeleted = "message_deleted"
+	EventMessageHidden  = "message_hidden"
+	EventReactions      = "reactions_updated"
+	EventMessagesRead   = "messages_read"
+	EventSignal         = "signal"
+	EventChatCreated    = "chat_created"
+	EventChatUpdated    = "chat_updated"
+	EventChatDeleted    = "chat_deleted"
+	EventMembersAdded   = "members_added"
+	EventMembersRemoved = "members_removed"
+	EventMembersUpdated = "members_updated"
+	EventJoinRequested  = "join_requested"
+	EventMemberBanned   = "member_banned"
+	EventMemberUnbanned = "member_unbanned"
+	EventMemberMuted    = "member_muted"
+	EventMemberUnmuted  = "member_unmuted"
+	EventOwnerChanged   = "owner_changed"
+	EventOwnerOffered   = "ownership_offered"
+	EventPinned         = "message_pinned"
+	EventUnpinned       = "message_unpinned"
+	EventPollUpdated    = "poll_updated"
+)
+
+type ChatEvent struct {
+	Type    string      `json:"type"`
+	ChatId  uuid.UUID   `json:"chat_id"`
+	UserIds []uuid.UUID `json:"user_ids,omitempty"`
+	Payload any         `json:"payload,omitempty"`
+}
+
+type EditMessageRequest struct {
+	ChatId    uuid.UUID `json:"chat_id"`
+	MessageId uuid.UUID `json:"message_id"`
+	Text      string    `json:"text"`
+}
+
+type DeleteMessageRequest struct {
+	ChatId      uuid.UUID `json:"chat_id"`
+	MessageId   uuid.UUID `json:"message_id"`
+	ForEveryone bool      `json:"for_everyone"`
+}
+
+type MessageDeletedPayload struct {
+	MessageId uuid.UUID `json:"message_id"`
+}
+
+type ThreadFollowRequest struct {
+	ChatId        uuid.UUID `json:"chat_id"`
+	RootMessageId uuid.UUID `json:"root_message_id"`
+}
+
+type ThreadSummaryResponse struct {
+	RootMessageId uuid.UUID `json:"root_message_id"`
+	Replies       int       `json:"replies"`
+	UnreadCount   int       `json:"unread_count"`
+}
+
+type ReactionRequest struct {
+	ChatId    uuid.UUID `json:"chat_id"`
+	MessageId uuid.UUID `json:"message_id"`
+	Emoji     string    `json:"emoji"`
+}
+
+type ReactionsPayload struct {
+	MessageId uuid.UUID              `json:"message_id"`
+	Reactions []models.ReactionCount `json:"reactions"`
+}
+
+type MarkReadRequest struct {
+	ChatId    uuid.UUID `json:"chat_id"`
+	MessageId uuid.UUID `json:"message_id"`
+}
+
+type MessagesReadPayload struct {
+	UserId    uuid.UUID `json:"user_id"`
+	MessageId uuid.UUID `json:"message_id"`
+}
+
+type MessageReadsResponse struct {
+	MessageId uuid.UUID   `json:"message_id"`
+	ReadBy    []uuid.UUID `json:"read_by,omitempty"`
+	Views     int         `json:"views"`
+}
+
+const (
+	SignalTyping         = "typing"
+	SignalRecordingVoice = "recording_voice"
+	SignalUploadingPhoto = "uploading_photo"
+)
+
+type SignalRequest struct {
+	ChatId uuid.UUID `json:"chat_id"`
+	Kind   string    `json:"kind"`
+}
+
+type SignalPayload struct {
+	UserId    uuid.UUID `json:"user_id"`
+	Kind      string    `json:"kind"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type SearchMessagesRequest struct {
+	Query         string
+	SenderId      *uuid.UUID
+	From          *time.Time
+	To            *time.Time
+	HasAttachment *bool
+	Cursor        string
+	Limit         int
+}
+
+type SearchResult struct {
+	Message models.Message `json:"message"`
+	Rank    float64        `json:"rank"`
+	Snippet string         `json:"snippet"`
+}
+
+type SearchMessagesResponse struct {
+	Results    []*SearchResult `json:"results"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+type DirectChatRequest struct {
+	UserId uuid.UUID `json:"user_id"`
+}
+
+type CreateInviteLinkRequest struct {
+	ChatId           uuid.UUID  `json:"chat_id"`
+	Name             string     `json:"name"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	MaxUses          int        `json:"max_uses"`
+	RequiresApproval bool       `json:"requires_approval"`
+}
+
+type InviteLinkRequest struct {
+	ChatId uuid.UUID `json:"chat_id"`
+	LinkId uuid.UUID `json:"link_id"`
+}
+
+type JoinRequestDecision struct {
+	ChatId    uuid.UUID `json:"chat_id"`
+	RequestId uuid.UUID `json:"request_id"`
+}
+
+const (
+	JoinStatusJoined  = "joined"
+	JoinStatusPending = "pending"
+)
+
+type JoinChatResponse struct {
+	Chat      *models.Chat `json:"chat"`
+	Status    string       `json:"status"`
+	RequestId *uuid.UUID   `json:"request_id,omitempty"`
+}
+
+type RestrictMemberRequest struct {
+	ChatId uuid.UUID `json:"chat_id"`
+	UserId uuid.UUID `json:"user_id"`
+	// Permanent when omitted.
+	Until  *time.Time `json:"until"`
+	Reason string     `json:"reason"`
+}
+
+type LiftRestrictionRequest struct {
+	ChatId uuid.UUID `json:"chat_id"`
+	UserId uuid.UUID `json:"user_id"`
+}
+
+type TransferOwnershipRequest struct {
+	ChatId uuid.UUID `json:"chat_id"`
+	UserId uuid.UUID `json:"user_id"`
+}
+
+type ConfirmOwnershipRequest struct {
+	ChatId uuid.UUID `json:"chat_id"`
+}
+
+type ChatIdRequest struct {
+	ChatId uuid.UUID `json:"chat_id"`
+}
+
+type OwnershipTransfer struct {
+	ChatId     uuid.UUID `json:"chat_id"`
+	FromUserId uuid.UUID `json:"from_user_id"`
+	ToUserId   uuid.UUID `json:"to_user_id"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type OwnerChangedPayload struct {
+	PreviousOwnerId uuid.UUID `json:"previous_owner_id"`
+	OwnerId         uuid.UUID `json:"owner_id"`
+}
+
+type ChatMember struct {
+	UserId      uuid.UUID         `json:"user_id"`
+	Role        string            `json:"role"`
+	AdminRights models.AdminRight `json:"admin_rights"`
+	JoinedAt    time.Time         `json:"joined_at"`
+	InvitedBy   *uuid.UUID        `json:"invited_by"`
+	// Filled from user_mgmt by the HTTP API.
+	Name       string `json:"name,omitempty"`
+	ProfilePic string `json:"profile_pic,omitempty"`
+}
+
+type MembersQuery struct {
+	Cursor string
+	Limit  int
+	// One of the models.MemberRole values, empty lists every role.
+	Role string
+	// Set when searching, nil lists every member.
+	UserIds []uuid.UUID
+}
+
+type MembersPageResponse struct {
+	Members    []ChatMember `json:"members"`
+	NextCursor string       `json:"next_cursor"`
+}
+
+type ChatMembership struct {
+	Chat *models.Chat
+	Role string
+}
+
+type MemberPermissions struct {
+	Role        string
+	Permissions models.Permission
+	AdminRights models.AdminRight
+}
+
+type PinMessageRequest struct {
+	ChatId    uuid.UUID `json:"chat_id"`
+	MessageId uuid.UUID `json:"message_id"`
+}
+
+type Pin struct {
+	Message  *models.Message `json:"message"`
+	PinnedBy uuid.UUID       `json:"pinned_by"`
+	PinnedAt time.Time       `json:"pinned_at"`
+}
+
+type MessagesUnpinnedPayload struct {
+	MessageIds []uuid.UUID `json:"message_ids"`
+}
+
+type SendPollRequest struct {
+	ChatId         uuid.UUID `json:"chat_id"`
+	Question       string    `json:"question"`
+	Options        []string  `json:"options"`
+	Public         bool      `json:"public"`
+	MultipleChoice bool      `json:"multiple_choice"`
+	Quiz           bool      `json:"quiz"`
+	// Quizzes only.
+	CorrectOption *int   `json:"correct_option"`
+	Explanation   string `json:"explanation"`
+	// Open until closed by hand when omitted.
+	ClosesAt *time.Time `json:"closes_at"`
+}
+
+type PollRequest struct {
+	ChatId    uuid.UUID `json:"chat_id"`
+	MessageId uuid.UUID `json:"message_id"`
+}
+
+type PollVoteRequest struct {
+	ChatId    uuid.UUID `json:"chat_id"`
+	MessageId uuid.UUID `json:"message_id"`
+	Options   []int     `json:"options"`
+}
+
+// Carries no viewer votes, quiz answers are only included once the poll is
+// closed.
+type PollUpdatedPayload struct {
+	MessageId     uuid.UUID  `json:"message_id"`
+	Tallies       []int      `json:"tallies"`
+	TotalVoters   int        `json:"total_voters"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty"`
+	CorrectOption *int       `json:"correct_option,omitempty"`
+	Explanation   string     `json:"explanation,omitempty"`
+}
+
+type PollVoter struct {
+	UserId  uuid.UUID `json:"user_id"`
+	Options []int     `json:"options"`
 }
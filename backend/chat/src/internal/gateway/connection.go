@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	framePing = "ping"
+	framePong = "pong"
+)
+
+type clientFrame struct {
+	Type   string    `json:"type"`
+	ChatId uuid.UUID `json:"chat_id"`
+	Kind   string    `json:"kind"`
+}
+
+type connection struct {
+	gateway *Gateway
+	ws      *websocket.Conn
+	userId  uuid.UUID
+	send    chan []byte
+	// chats, loaded and pending are guarded by gateway.mu. Events that arrive
+	// while the chat set is still loading wait in pending.
+	chats   map[uuid.UUID]struct{}
+	loaded  bool
+	pending []pendingEvent
+}
+
+type pendingEvent struct {
+	event   *frame
+	payload []byte
+}
+
+func (c *connection) readPump() {
+	defer func() {
+		c.gateway.unregister(c)
+		c.ws.Close()
+	}()
+	c.ws.SetReadLimit(maxMessageSize)
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		_, message, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				slog.Error("Gateway read error", "error", err.Error(), "userId", c.userId)
+			}
+			return
+		}
+		c.ws.SetReadDeadline(time.Now().Add(pongWait))
+		var incoming clientFrame
+		if json.Unmarshal(message, &incoming) != nil {
+			continue
+		}
+		switch incoming.Type {
+		case framePing:
+			pong, _ := json.Marshal(&frame{Type: framePong})
+			c.gateway.deliverTo(c, pong)
+		case dto.EventSignal:
+			err := c.gateway.service.EmitSignal(c.userId, &dto.SignalRequest{ChatId: incoming.ChatId, Kind: incoming.Kind})
+			if err != nil {
+				slog.Debug("Signal rejected", "error", err.Error(), "userId", c.userId)
+			}
+		}
+	}
+}
+
+func (c *connection) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+		slog.Info("Gateway connection closed", "userId", c.userId)
+	}()
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.ws.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			err := c.ws.WriteMessage(websocket.TextMessage, message)
+			if err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			err := c.ws.WriteMessage(websocket.PingMessage, nil)
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/client"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/service"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 4096
+	sendBufferSize = 256
+)
+
+type Gateway struct {
+	service     *service.ChatManagementService
+	authClient  *client.AuthGRPCClient
+	upgrader    websocket.Upgrader
+	mu          sync.Mutex
+	connections map[*connection]struct{}
+}
+
+func New(service *service.ChatManagementService, authClient *client.AuthGRPCClient, allowedOrigins []string) *Gateway {
+	return &Gateway{
+		service:    service,
+		authClient: authClient,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin(allowedOrigins),
+		},
+		connections: make(map[*connection]struct{}),
+	}
+}
+
+// Every gateway instance receives every event from Redis and delivers it to
+// the connections it holds locally.
+func (g *Gateway) Run(ctx context.Context) {
+	pubsub := g.service.SubscribeChatEvents(ctx)
+	defer pubsub.Close()
+	for msg := range pubsub.Channel() {
+		var event frame
+		err := json.Unmarshal([]byte(msg.Payload), &event)
+		if err != nil {
+			slog.Error("Failed to decode chat event", "error", err.Error())
+			continue
+		}
+		g.dispatch(&event, []byte(msg.Payload))
+	}
+}
+
+// The gateway authenticates browsers by cookie, so a page from any other
+// origin could read the visitor's events. Only the gateway's own host and the
+// configured origins pass. Clients that send no Origin are not browsers.
+func checkOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		if strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		return slices.ContainsFunc(allowedOrigins, func(allowed string) bool {
+			return strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin)
+		})
+	}
+}
+
+func (g *Gateway) HandleConnection(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" {
+		cookie, err := r.Cookie("Authorization")
+		if err != nil {
+			http.Error(w, "authorization is missing", http.StatusUnauthorized)
+			return
+		}
+		r.Header.Set("Authorization", "Bearer "+cookie.Value)
+	}
+	authResp, err := g.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		slog.Error("Authorization error", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Registered before the chats are loaded so that membership events
+	// published in between are not lost.
+	conn := &connection{
+		gateway: g,
+		userId:  userId,
+		send:    make(chan []byte, sendBufferSize),
+		chats:   make(map[uuid.UUID]struct{}),
+	}
+	g.register(conn)
+	chats, err := g.service.GetAllChatsForUser(userId)
+	if err != nil {
+		g.unregister(conn)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ws, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		g.unregister(conn)
+		slog.Error("Failed to upgrade connection", "error", err.Error())
+		return
+	}
+	conn.ws = ws
+	g.chatsLoaded(conn, chats)
+	slog.Info("Gateway connection opened", "userId", userId, "chats", len(chats))
+	go conn.writePump()
+	go conn.readPump()
+}
+
+func (g *Gateway) register(conn *connection) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.connections[conn] = struct{}{}
+}
+
+// Replays what arrived while the chats were loading on top of the loaded set.
+func (g *Gateway) chatsLoaded(conn *connection, chats []*models.Chat) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, chat := range chats {
+		conn.chats[chat.Id] = struct{}{}
+	}
+	conn.loaded = true
+	pending := conn.pending
+	conn.pending = nil
+	for _, p := range pending {
+		if _, ok := g.connections[conn]; !ok {
+			return
+		}
+		g.dispatchTo(conn, p.event, p.payload)
+	}
+}
+
+func (g *Gateway) unregister(conn *connection) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.connections[conn]; ok {
+		delete(g.connections, conn)
+		close(conn.send)
+	}
+}
+
+func (g *Gateway) dispatch(event *frame, payload []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for conn := range g.connections {
+		if !conn.loaded {
+			conn.pending = append(conn.pending, pendingEvent{event: event, payload: payload})
+			continue
+		}
+		g.dispatchTo(conn, event, payload)
+	}
+}
+
+// Must be called with g.mu held.
+func (g *Gateway) dispatchTo(conn *connection, event *frame, payload []byte) {
+	targeted := event.targets(conn.userId)
+	switch event.Type {
+	case dto.EventChatCreated, dto.EventMembersAdded:
+		if targeted {
+			conn.chats[event.ChatId] = struct{}{}
+		}
+	}
+	if _, ok := conn.chats[event.ChatId]; !ok {
+		return
+	}
+	if (event.Type == dto.EventMessageHidden || event.Type == dto.EventJoinRequested || event.Type == dto.EventOwnerOffered) && !targeted {
+		return
+	}
+	if event.Type == dto.EventSignal && targeted {
+		return
+	}
+	g.deliver(conn, payload)
+	switch event.Type {
+	case dto.EventChatDeleted:
+		delete(conn.chats, event.ChatId)
+	case dto.EventMembersRemoved:
+		if targeted {
+			delete(conn.chats, event.ChatId)
+		}
+	}
+}
+
+// Must be called with g.mu held. A consumer whose buffer is full is
+// disconnected instead of stalling delivery for everyone else; the client is
+// expected to reconnect and resync over REST.
+func (g *Gateway) deliver(conn *connection, payload []byte) {
+	select {
+	case conn.send <- payload:
+	default:
+		slog.Warn("Dropping slow gateway consumer", "userId", conn.userId)
+		delete(g.connections, conn)
+		close(conn.send)
+	}
+}
+
+func (g *Gateway) deliverTo(conn *connection, payload []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.connections[conn]; ok {
+		g.deliver(conn, payload)
+	}
+}
+
+type frame struct {
+	Type    string      `json:"type"`
+	ChatId  uuid.UUID   `json:"chat_id"`
+	UserIds []uuid.UUID `json:"user_ids,omitempty"`
+}
+
+func (f *frame) targets(userId uuid.UUID) bool {
+	for _, id := range f.UserIds {
+		if id == userId {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,14 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
 )
 
 type Chat struct {
@@ -10,15 +16,274 @@ type Chat struct {
 	Name        string    `gorm:"not null;check:name <> ''"`
 	CreatorId   uuid.UUID `gorm:"type:uuid"`
 	IsChannel   bool      `gorm:"not null;default:false"`
+	IsDirect    bool      `gorm:"not null;default:false"`
 	JoinLink    string    `gorm:"unique;not null;check:join_link <> ''"`
 	Description string
 	ProfilePic  string
+	// An empty set allows any reaction.
+	AllowedReactions pq.StringArray `gorm:"type:text[]"`
+	ReadersCanReact  bool           `gorm:"not null;default:false"`
+	ReadersCanVote   bool           `gorm:"not null;default:false"`
+	// Applies to members without admin rights, admins are not limited by it.
+	MemberPermissions Permission `gorm:"not null;default:3"`
+	// An upgraded group with a higher member limit and paged member lists.
+	IsSupergroup bool `gorm:"not null;default:false"`
+}
+
+// Direct chats have no owner, their CreatorId only records who opened them.
+func (c *Chat) IsOwner(userId uuid.UUID) bool {
+	return !c.IsDirect && c.CreatorId == userId
 }
 
+type Permission int32
+
+const (
+	PermSendMessages Permission = 1 << iota
+	PermSendMedia
+	PermAddMembers
+	PermPinMessages
+	PermChangeInfo
+
+	AllPermissions           = PermSendMessages | PermSendMedia | PermAddMembers | PermPinMessages | PermChangeInfo
+	DefaultMemberPermissions = PermSendMessages | PermSendMedia
+)
+
+type AdminRight int32
+
+const (
+	RightBanUsers AdminRight = 1 << iota
+	RightDeleteMessages
+	RightPromoteMembers
+
+	AllAdminRights = RightBanUsers | RightDeleteMessages | RightPromoteMembers
+)
+
 type UserChat struct {
 	gorm.Model
 	ChatId   uuid.UUID `gorm:"type:uuid"`
 	UserId   uuid.UUID `gorm:"type:uuid"`
 	ReadOnly bool
 	IsAdmin  bool
+	// Only meaningful for admins. Admins promoted before rights existed get
+	// all of them.
+	AdminRights AdminRight `gorm:"not null;default:7"`
+	// Who added the member or created the invite link they joined with.
+	InvitedBy *uuid.UUID `gorm:"type:uuid"`
+	// Read position, LastReadAt is the creation time of LastReadMessageId.
+	LastReadMessageId *uuid.UUID `gorm:"type:uuid"`
+	LastReadAt        *time.Time
+}
+
+// Owners are admins too, so filtering by MemberRoleAdmin includes them.
+const (
+	MemberRoleOwner  = "owner"
+	MemberRoleAdmin  = "admin"
+	MemberRoleMember = "member"
+	MemberRoleReader = "reader"
+)
+
+type Message struct {
+	Id          uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	ChatId      uuid.UUID      `gorm:"type:uuid;not null;index:idx_messages_chat_created" json:"chat_id"`
+	SenderId    uuid.UUID      `gorm:"type:uuid;not null" json:"sender_id"`
+	Text        string         `json:"text"`
+	Attachments pq.StringArray `gorm:"type:text[]" json:"attachments"`
+	// The reply snapshot keeps the quote readable after the parent is edited
+	// or deleted.
+	ReplyToId       *uuid.UUID      `gorm:"type:uuid" json:"reply_to_id,omitempty"`
+	ReplyToSenderId *uuid.UUID      `gorm:"type:uuid" json:"reply_to_sender_id,omitempty"`
+	ReplyToText     string          `json:"reply_to_text,omitempty"`
+	ThreadRootId    *uuid.UUID      `gorm:"type:uuid;index" json:"thread_root_id,omitempty"`
+	ThreadReplies   int             `gorm:"-" json:"thread_replies"`
+	Reactions       []ReactionCount `gorm:"-" json:"reactions"`
+	CreatedAt       time.Time       `gorm:"not null;index:idx_messages_chat_created" json:"created_at"`
+	EditedAt        *time.Time      `json:"edited_at"`
+	DeletedAt       *time.Time      `sql:"index" json:"-"`
+	// Service messages are written by the chat itself and rendered by
+	// clients from Action rather than Text.
+	Type   string         `gorm:"not null;default:'text'" json:"type"`
+	Action *ServiceAction `gorm:"type:jsonb" json:"action,omitempty"`
+	Poll   *Poll          `gorm:"-" json:"poll,omitempty"`
+}
+
+const (
+	MessageTypeText    = "text"
+	MessageTypeService = "service"
+	MessageTypePoll    = "poll"
+)
+
+const (
+	ServiceMessagePinned         = "message_pinned"
+	ServiceMessageChatCreated    = "chat_created"
+	ServiceMessageMembersAdded   = "members_added"
+	ServiceMessageMembersRemoved = "members_removed"
+	ServiceMessageMemberJoined   = "member_joined"
+	ServiceMessageAdminsAdded    = "admins_added"
+	ServiceMessageTitleChanged   = "title_changed"
+	ServiceMessagePhotoChanged   = "photo_changed"
+)
+
+// The structured part of a service message, clients build the localized text
+// from it. The sender of the message is the user who caused it.
+type ServiceAction struct {
+	Type      string      `json:"type"`
+	MessageId *uuid.UUID  `json:"message_id,omitempty"`
+	UserIds   []uuid.UUID `json:"user_ids,omitempty"`
+	Title     string      `json:"title,omitempty"`
+	Photo     string      `json:"photo,omitempty"`
+}
+
+func (a ServiceAction) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *ServiceAction) Scan(src any) error {
+	bytes, ok := src.([]byte)
+	if !ok {
+		return errors.New("service action is not jsonb")
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// A poll is stored next to the message that carries it and shares its id.
+// The message text holds the question so previews and search keep working.
+type Poll struct {
+	MessageId      uuid.UUID      `gorm:"type:uuid;primary_key" json:"message_id"`
+	ChatId         uuid.UUID      `gorm:"type:uuid;not null;index" json:"chat_id"`
+	Question       string         `gorm:"not null" json:"question"`
+	Options        pq.StringArray `gorm:"type:text[];not null" json:"options"`
+	Public         bool           `gorm:"not null;default:false" json:"public"`
+	MultipleChoice bool           `gorm:"not null;default:false" json:"multiple_choice"`
+	Quiz           bool           `gorm:"not null;default:false" json:"quiz"`
+	// Only revealed to voters and once the poll is closed.
+	CorrectOption *int       `json:"correct_option,omitempty"`
+	Explanation   string     `json:"explanation,omitempty"`
+	ClosesAt      *time.Time `gorm:"index" json:"closes_at"`
+	ClosedAt      *time.Time `json:"closed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	// Filled per viewer, Voted holds the options the viewer picked.
+	Tallies     []int `gorm:"-" json:"tallies"`
+	TotalVoters int   `gorm:"-" json:"total_voters"`
+	Voted       []int `gorm:"-" json:"voted"`
+}
+
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.ClosedAt != nil || (p.ClosesAt != nil && !p.ClosesAt.After(now))
+}
+
+type PollVote struct {
+	MessageId uuid.UUID `gorm:"type:uuid;primary_key" json:"message_id"`
+	UserId    uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+	Option    int       `gorm:"primary_key;auto_increment:false" json:"option"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PollTally struct {
+	MessageId uuid.UUID
+	Option    int
+	Count     int
+}
+
+type PinnedMessage struct {
+	ChatId    uuid.UUID `gorm:"type:uuid;primary_key" json:"chat_id"`
+	MessageId uuid.UUID `gorm:"type:uuid;primary_key" json:"message_id"`
+	PinnedBy  uuid.UUID `gorm:"type:uuid;not null" json:"pinned_by"`
+	PinnedAt  time.Time `gorm:"not null" json:"pinned_at"`
+}
+
+type MessageEdit struct {
+	Id        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	MessageId uuid.UUID `gorm:"type:uuid;not null;index" json:"message_id"`
+	EditorId  uuid.UUID `gorm:"type:uuid;not null" json:"editor_id"`
+	Text      string    `json:"text"`
+	EditedAt  time.Time `gorm:"not null" json:"edited_at"`
+}
+
+type MessageDeletion struct {
+	MessageId uuid.UUID `gorm:"type:uuid;primary_key" json:"message_id"`
+	UserId    uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+}
+
+type ThreadFollower struct {
+	RootMessageId uuid.UUID `gorm:"type:uuid;primary_key" json:"root_message_id"`
+	UserId        uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+	ChatId        uuid.UUID `gorm:"type:uuid;not null;index" json:"chat_id"`
+	Following     bool      `gorm:"not null" json:"following"`
+	LastReadAt    time.Time `json:"last_read_at"`
+}
+
+type Reaction struct {
+	MessageId uuid.UUID `gorm:"type:uuid;primary_key" json:"message_id"`
+	UserId    uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+	Emoji     string    `gorm:"primary_key" json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ReactionCount struct {
+	MessageId uuid.UUID `json:"-"`
+	Emoji     string    `json:"emoji"`
+	Count     int       `json:"count"`
+	Reacted   bool      `json:"reacted"`
+}
+
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+type InviteLink struct {
+	Id               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	ChatId           uuid.UUID  `gorm:"type:uuid;not null;index" json:"chat_id"`
+	Token            string     `gorm:"unique;not null" json:"token"`
+	Name             string     `json:"name"`
+	CreatorId        uuid.UUID  `gorm:"type:uuid;not null" json:"creator_id"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	MaxUses          int        `gorm:"not null;default:0" json:"max_uses"`
+	Uses             int        `gorm:"not null;default:0" json:"uses"`
+	RequiresApproval bool       `gorm:"not null;default:false" json:"requires_approval"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+func (l *InviteLink) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !l.ExpiresAt.After(now)
+}
+
+type InviteJoin struct {
+	Id           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	ChatId       uuid.UUID  `gorm:"type:uuid;not null;index" json:"chat_id"`
+	UserId       uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	InviteLinkId *uuid.UUID `gorm:"type:uuid;index" json:"invite_link_id"`
+	JoinedAt     time.Time  `json:"joined_at"`
+}
+
+type JoinRequest struct {
+	Id           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	ChatId       uuid.UUID  `gorm:"type:uuid;not null;index" json:"chat_id"`
+	UserId       uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	InviteLinkId uuid.UUID  `gorm:"type:uuid;not null" json:"invite_link_id"`
+	Status       string     `gorm:"not null;default:'pending'" json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DecidedBy    *uuid.UUID `gorm:"type:uuid" json:"decided_by"`
+	DecidedAt    *time.Time `json:"decided_at"`
+}
+
+const (
+	RestrictionBan  = "ban"
+	RestrictionMute = "mute"
+)
+
+// A ban keeps the user out of the chat, a mute turns a member into a reader.
+// Both are active until ExpiresAt, or until lifted when ExpiresAt is nil.
+type ChatRestriction struct {
+	Id        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	ChatId    uuid.UUID  `gorm:"type:uuid;not null;index:idx_chat_restrictions_chat_user" json:"chat_id"`
+	UserId    uuid.UUID  `gorm:"type:uuid;not null;index:idx_chat_restrictions_chat_user" json:"user_id"`
+	Kind      string     `gorm:"not null" json:"kind"`
+	Reason    string     `json:"reason"`
+	CreatedBy uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at"`
+	LiftedAt  *time.Time `json:"lifted_at"`
 }
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+func (r *ChatRepository) PublishInChatEventsChannel(message interface{}) error {
+	return r.redis.Publish(context.Background(), "chat-events-channel", message).Err()
+}
+
+func (r *ChatRepository) SubscribeChatEventsChannel(ctx context.Context) *redis.PubSub {
+	return r.redis.Subscribe(ctx, "chat-events-channel")
+}
+
+// Returns false when the user already emitted a signal in this chat within
+// the window.
+func (r *ChatRepository) AcquireSignalSlot(chatId uuid.UUID, userId uuid.UUID, window time.Duration) (bool, error) {
+	return r.redis.SetNX(context.Background(), fmt.Sprintf("SIGNAL_%s_%s", chatId, userId), 1, window).Result()
+}
+
+func (r *ChatRepository) SaveOwnershipTransfer(chatId uuid.UUID, transfer []byte, ttl time.Duration) error {
+	return r.redis.Set(context.Background(), fmt.Sprintf("OWNERSHIP_TRANSFER_%s", chatId), transfer, ttl).Err()
+}
+
+func (r *ChatRepository) FindOwnershipTransfer(chatId uuid.UUID) ([]byte, error) {
+	return r.redis.Get(context.Background(), fmt.Sprintf("OWNERSHIP_TRANSFER_%s", chatId)).Bytes()
+}
+
+func (r *ChatRepository) DeleteOwnershipTransfer(chatId uuid.UUID) error {
+	return r.redis.Del(context.Background(), fmt.Sprintf("OWNERSHIP_TRANSFER_%s", chatId)).Err()
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+func (r *ChatRepository) SaveInviteLink(link *models.InviteLink) error {
+	return r.db.Save(link).Error
+}
+
+func (r *ChatRepository) FindInviteLinkByToken(token string) (*models.InviteLink, error) {
+	var link models.InviteLink
+	err := r.db.Where("token = ?", token).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ChatRepository) FindInviteLink(chatId uuid.UUID, linkId uuid.UUID) (*models.InviteLink, error) {
+	var link models.InviteLink
+	err := r.db.Where("id = ? AND chat_id = ?", linkId, chatId).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ChatRepository) GetInviteLinks(chatId uuid.UUID) ([]models.InviteLink, error) {
+	var links []models.InviteLink
+	err := r.db.Where("chat_id = ?", chatId).Order("created_at asc").Find(&links).Error
+	return links, err
+}
+
+// Takes one use of the link, failing when it has none left.
+func (r *ChatRepository) ConsumeInviteLinkUse(linkId uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.InviteLink{}).
+		Where("id = ? AND (max_uses = 0 OR uses < max_uses)", linkId).
+		UpdateColumn("uses", gorm.Expr("uses + 1"))
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ChatRepository) SaveInviteJoin(join *models.InviteJoin) error {
+	return r.db.Create(join).Error
+}
+
+func (r *ChatRepository) GetInviteJoins(chatId uuid.UUID, linkId *uuid.UUID) ([]models.InviteJoin, error) {
+	var joins []models.InviteJoin
+	query := r.db.Where("chat_id = ?", chatId)
+	if linkId != nil {
+		query = query.Where("invite_link_id = ?", *linkId)
+	}
+	err := query.Order("joined_at asc").Find(&joins).Error
+	return joins, err
+}
+
+func (r *ChatRepository) SaveJoinRequest(request *models.JoinRequest) error {
+	return r.db.Save(request).Error
+}
+
+func (r *ChatRepository) FindPendingJoinRequest(chatId uuid.UUID, userId uuid.UUID) (*models.JoinRequest, error) {
+	var request models.JoinRequest
+	err := r.db.Where("chat_id = ? AND user_id = ? AND status = ?", chatId, userId, models.JoinRequestPending).First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *ChatRepository) GetPendingJoinRequests(chatId uuid.UUID) ([]models.JoinRequest, error) {
+	var requests []models.JoinRequest
+	err := r.db.Where("chat_id = ? AND status = ?", chatId, models.JoinRequestPending).Order("created_at asc").Find(&requests).Error
+	return requests, err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+func (r *ChatRepository) FindUserChat(chatId uuid.UUID, userId uuid.UUID) (*models.UserChat, error) {
+	var userChat models.UserChat
+	err := r.db.Where("chat_id = ? AND user_id = ?", chatId, userId).First(&userChat).Error
+	if err != nil {
+		return nil, err
+	}
+	return &userChat, nil
+}
+
+func (r *ChatRepository) SaveMessage(message *models.Message) error {
+	return r.db.Create(message).Error
+}
+
+func (r *ChatRepository) FindMessageById(messageId uuid.UUID) (*models.Message, error) {
+	var message models.Message
+	err := r.db.Where("id = ?", messageId).First(&message).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// Messages are returned newest first, without the ones the viewer deleted for
+// themselves. Without threadRootId only the main timeline is returned. When
+// before is set only messages older than (beforeCreatedAt, beforeId) are
+// returned.
+func (r *ChatRepository) GetMessages(chatId uuid.UUID, viewerId uuid.UUID, threadRootId *uuid.UUID, beforeCreatedAt *time.Time, beforeId uuid.UUID, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	query := r.db.Where("chat_id = ?", chatId).
+		Where("NOT EXISTS (SELECT 1 FROM message_deletions md WHERE md.message_id = messages.id AND md.user_id = ?)", viewerId)
+	if threadRootId == nil {
+		query = query.Where("thread_root_id IS NULL")
+	} else {
+		query = query.Where("thread_root_id = ?", *threadRootId)
+	}
+	if beforeCreatedAt != nil {
+		query = query.Where("(created_at, id) < (?, ?)", *beforeCreatedAt, beforeId)
+	}
+	err := query.Order("created_at desc, id desc").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+func (r *ChatRepository) AppendAttachment(messageId uuid.UUID, fileId uuid.UUID) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ? AND NOT (? = ANY(coalesce(attachments, '{}')))", messageId, fileId.String()).
+		Update("attachments", gorm.Expr("array_append(coalesce(attachments, '{}'), ?)", fileId.String())).Error
+}
+
+func (r *ChatRepository) SubscribeFileLoadedChannel(ctx context.Context) *redis.PubSub {
+	return r.redis.Subscribe(ctx, "file-loaded-channel")
+}
+
+func (r *ChatRepository) UpdateMessageText(message *models.Message, edit *models.MessageEdit) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	err := tx.Create(edit).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Model(message).Updates(map[string]interface{}{"text": message.Text, "edited_at": message.EditedAt}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (r *ChatRepository) GetMessageEdits(messageId uuid.UUID) ([]models.MessageEdit, error) {
+	var edits []models.MessageEdit
+	err := r.db.Where("message_id = ?", messageId).Order("edited_at asc").Find(&edits).Error
+	return edits, err
+}
+
+func (r *ChatRepository) DeleteMessage(messageId uuid.UUID) error {
+	return r.db.Where("id = ?", messageId).Delete(&models.Message{}).Error
+}
+
+func (r *ChatRepository) HideMessage(deletion *models.MessageDeletion) error {
+	return r.db.Where(deletion).FirstOrCreate(deletion).Error
+}
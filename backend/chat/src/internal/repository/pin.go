@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+// Pinning a pinned message again moves it to the top.
+func (r *ChatRepository) SavePin(pin *models.PinnedMessage) error {
+	return r.db.Exec(
+		`INSERT INTO pinned_messages (chat_id, message_id, pinned_by, pinned_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (chat_id, message_id) DO UPDATE SET pinned_by = EXCLUDED.pinned_by, pinned_at = EXCLUDED.pinned_at`,
+		pin.ChatId, pin.MessageId, pin.PinnedBy, pin.PinnedAt,
+	).Error
+}
+
+// Latest pin first, pins of deleted messages are skipped.
+func (r *ChatRepository) GetPins(chatId uuid.UUID, limit int) ([]models.PinnedMessage, error) {
+	var pins []models.PinnedMessage
+	query := r.db.
+		Joins("JOIN messages ON messages.id = pinned_messages.message_id AND messages.deleted_at IS NULL").
+		Where("pinned_messages.chat_id = ?", chatId).
+		Order("pinned_messages.pinned_at desc")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&pins).Error
+	return pins, err
+}
+
+func (r *ChatRepository) GetMessagesByIds(messageIds []uuid.UUID) ([]models.Message, error) {
+	var messages []models.Message
+	err := r.db.Where("id IN (?)", messageIds).Find(&messages).Error
+	return messages, err
+}
+
+// Reports whether a pin was removed.
+func (r *ChatRepository) DeletePin(chatId uuid.UUID, messageId uuid.UUID) (bool, error) {
+	result := r.db.Where("chat_id = ? AND message_id = ?", chatId, messageId).Delete(&models.PinnedMessage{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ChatRepository) DeletePins(chatId uuid.UUID) error {
+	return r.db.Where("chat_id = ?", chatId).Delete(&models.PinnedMessage{}).Error
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+func (r *ChatRepository) SavePoll(poll *models.Poll) error {
+	return r.db.Create(poll).Error
+}
+
+// Serializes votes on one poll until the transaction ends.
+func (r *ChatRepository) LockPoll(chatId uuid.UUID, messageId uuid.UUID) (*models.Poll, error) {
+	var poll models.Poll
+	err := r.db.Set("gorm:query_option", "FOR UPDATE").
+		Joins("JOIN messages ON messages.id = polls.message_id AND messages.deleted_at IS NULL").
+		Where("polls.message_id = ? AND polls.chat_id = ?", messageId, chatId).
+		First(&poll).Error
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+func (r *ChatRepository) GetPolls(messageIds []uuid.UUID) ([]models.Poll, error) {
+	var polls []models.Poll
+	if len(messageIds) == 0 {
+		return polls, nil
+	}
+	err := r.db.Where("message_id IN (?)", messageIds).Find(&polls).Error
+	return polls, err
+}
+
+func (r *ChatRepository) InsertPollVotes(messageId uuid.UUID, userId uuid.UUID, options []int) error {
+	for _, option := range options {
+		err := r.db.Exec(
+			`INSERT INTO poll_votes (message_id, user_id, option, created_at) VALUES (?, ?, ?, now())
+			ON CONFLICT (message_id, user_id, option) DO NOTHING`,
+			messageId, userId, option,
+		).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reports whether the user had voted.
+func (r *ChatRepository) DeletePollVotes(messageId uuid.UUID, userId uuid.UUID) (bool, error) {
+	result := r.db.Where("message_id = ? AND user_id = ?", messageId, userId).Delete(&models.PollVote{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ChatRepository) GetPollTallies(messageIds []uuid.UUID) ([]models.PollTally, error) {
+	var tallies []models.PollTally
+	if len(messageIds) == 0 {
+		return tallies, nil
+	}
+	err := r.db.Model(&models.PollVote{}).
+		Select("message_id, option, count(*) AS count").
+		Where("message_id IN (?)", messageIds).
+		Group("message_id, option").
+		Scan(&tallies).Error
+	return tallies, err
+}
+
+type pollVoters struct {
+	MessageId uuid.UUID
+	Count     int
+}
+
+func (r *ChatRepository) CountPollVoters(messageIds []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(messageIds) == 0 {
+		return counts, nil
+	}
+	var rows []pollVoters
+	err := r.db.Model(&models.PollVote{}).
+		Select("message_id, count(DISTINCT user_id) AS count").
+		Where("message_id IN (?)", messageIds).
+		Group("message_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.MessageId] = row.Count
+	}
+	return counts, nil
+}
+
+// Pass a nil userId to get every vote, public polls list their voters.
+func (r *ChatRepository) GetPollVotes(messageIds []uuid.UUID, userId *uuid.UUID) ([]models.PollVote, error) {
+	var votes []models.PollVote
+	if len(messageIds) == 0 {
+		return votes, nil
+	}
+	query := r.db.Where("message_id IN (?)", messageIds)
+	if userId != nil {
+		query = query.Where("user_id = ?", *userId)
+	}
+	err := query.Order("created_at asc, option asc").Find(&votes).Error
+	return votes, err
+}
+
+// Reports false when the poll was already closed.
+func (r *ChatRepository) ClosePoll(messageId uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Poll{}).
+		Where("message_id = ? AND closed_at IS NULL", messageId).
+		UpdateColumn("closed_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Polls whose close time passed but that nobody closed yet. Polls of deleted
+// messages are left alone.
+func (r *ChatRepository) GetExpiredPolls(now time.Time, limit int) ([]models.Poll, error) {
+	var polls []models.Poll
+	err := r.db.
+		Joins("JOIN messages ON messages.id = polls.message_id AND messages.deleted_at IS NULL").
+		Where("polls.closed_at IS NULL AND polls.closes_at <= ?", now).
+		Order("polls.closes_at asc").
+		Limit(limit).
+		Find(&polls).Error
+	return polls, err
+}
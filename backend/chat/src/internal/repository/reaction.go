@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+func (r *ChatRepository) AddReaction(reaction *models.Reaction) error {
+	return r.db.Where(models.Reaction{MessageId: reaction.MessageId, UserId: reaction.UserId, Emoji: reaction.Emoji}).FirstOrCreate(reaction).Error
+}
+
+func (r *ChatRepository) RemoveReaction(messageId uuid.UUID, userId uuid.UUID, emoji string) error {
+	return r.db.Where("message_id = ? AND user_id = ? AND emoji = ?", messageId, userId, emoji).Delete(&models.Reaction{}).Error
+}
+
+// Counts are ordered by first use so clients render a stable order.
+func (r *ChatRepository) GetReactionCounts(messageIds []uuid.UUID, viewerId uuid.UUID) ([]models.ReactionCount, error) {
+	var counts []models.ReactionCount
+	if len(messageIds) == 0 {
+		return counts, nil
+	}
+	err := r.db.Model(&models.Reaction{}).
+		Select("message_id, emoji, count(*) AS count, bool_or(user_id = ?) AS reacted", viewerId).
+		Where("message_id IN (?)", messageIds).
+		Group("message_id, emoji").
+		Order("min(created_at) asc").
+		Scan(&counts).Error
+	return counts, err
+}
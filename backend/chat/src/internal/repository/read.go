@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+// The read position only moves forward, an older message leaves it as is.
+func (r *ChatRepository) AdvanceReadPosition(chatId uuid.UUID, userId uuid.UUID, messageId uuid.UUID, readAt time.Time) (bool, error) {
+	result := r.db.Model(&models.UserChat{}).
+		Where("chat_id = ? AND user_id = ? AND (last_read_at IS NULL OR last_read_at < ?)", chatId, userId, readAt).
+		UpdateColumns(map[string]interface{}{"last_read_message_id": messageId, "last_read_at": readAt})
+	return result.RowsAffected > 0, result.Error
+}
+
+type chatUnread struct {
+	ChatId uuid.UUID
+	Count  int
+}
+
+// Counts per chat what the user has not read since their own read position.
+func (r *ChatRepository) CountUnreadByChat(chatIds []uuid.UUID, userId uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(chatIds) == 0 {
+		return counts, nil
+	}
+	var rows []chatUnread
+	err := r.db.Raw(
+		`SELECT m.chat_id, count(*) AS count FROM messages m
+		JOIN user_chats uc ON uc.chat_id = m.chat_id AND uc.user_id = ? AND uc.deleted_at IS NULL
+		WHERE m.chat_id IN (?) AND m.deleted_at IS NULL AND m.thread_root_id IS NULL AND m.sender_id <> ?
+		AND (uc.last_read_at IS NULL OR m.created_at > uc.last_read_at)
+		AND NOT EXISTS (SELECT 1 FROM message_deletions md WHERE md.message_id = m.id AND md.user_id = ?)
+		GROUP BY m.chat_id`,
+		userId, chatIds, userId, userId,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ChatId] = row.Count
+	}
+	return counts, nil
+}
+
+// The newest main timeline message of each chat the viewer still sees.
+func (r *ChatRepository) FindLastMessages(chatIds []uuid.UUID, viewerId uuid.UUID) ([]models.Message, error) {
+	var messages []models.Message
+	if len(chatIds) == 0 {
+		return messages, nil
+	}
+	err := r.db.Raw(
+		`SELECT DISTINCT ON (m.chat_id) m.* FROM messages m
+		WHERE m.chat_id IN (?) AND m.deleted_at IS NULL AND m.thread_root_id IS NULL
+		AND NOT EXISTS (SELECT 1 FROM message_deletions md WHERE md.message_id = m.id AND md.user_id = ?)
+		ORDER BY m.chat_id, m.created_at desc, m.id desc`,
+		chatIds, viewerId,
+	).Scan(&messages).Error
+	return messages, err
+}
+
+func (r *ChatRepository) GetMessageReaders(chatId uuid.UUID, readAt time.Time, excludeUserId uuid.UUID) ([]models.UserChat, error) {
+	var userChats []models.UserChat
+	err := r.db.Where("chat_id = ? AND last_read_at >= ? AND user_id <> ?", chatId, readAt, excludeUserId).Find(&userChats).Error
+	return userChats, err
+}
+
+func (r *ChatRepository) CountMessageViews(chatId uuid.UUID, readAt time.Time, excludeUserId uuid.UUID) (int, error) {
+	var count int
+	err := r.db.Model(&models.UserChat{}).
+		Where("chat_id = ? AND last_read_at >= ? AND user_id <> ?", chatId, readAt, excludeUserId).
+		Count(&count).Error
+	return count, err
+}
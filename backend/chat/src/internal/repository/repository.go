@@ -2,17 +2,19 @@ package repository
 
 import (
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 	_ "github.com/lib/pq"
 )
 
 type ChatRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	redis *redis.Client
 }
 
-func NewChatRepository(db *gorm.DB) *ChatRepository {
-	return &ChatRepository{db: db}
+func NewChatRepository(db *gorm.DB, redis *redis.Client) *ChatRepository {
+	return &ChatRepository{db: db, redis: redis}
 }
 
 func (r *ChatRepository) FindById(chatId uuid.UUID) (*models.Chat, error) {
@@ -21,6 +23,22 @@ func (r *ChatRepository) FindById(chatId uuid.UUID) (*models.Chat, error) {
 	return &chat, err
 }
 
+func (r *ChatRepository) FindByIds(chatIds []uuid.UUID) (map[uuid.UUID]*models.Chat, error) {
+	chatsById := make(map[uuid.UUID]*models.Chat, len(chatIds))
+	if len(chatIds) == 0 {
+		return chatsById, nil
+	}
+	var chats []models.Chat
+	err := r.db.Where("id IN (?)", chatIds).Find(&chats).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range chats {
+		chatsById[chats[i].Id] = &chats[i]
+	}
+	return chatsById, nil
+}
+
 func (r *ChatRepository) FindByJoinLink(joinLink string) (*models.Chat, error) {
 	var chat models.Chat
 	err := r.db.Where("join_link = ?", joinLink).First(&chat).Error
@@ -64,8 +82,64 @@ func (r *ChatRepository) GetChatUsers(chatID uuid.UUID) ([]models.UserChat, erro
 	return userChats, nil
 }
 
+func (r *ChatRepository) GetChatAdmins(chatId uuid.UUID) ([]models.UserChat, error) {
+	var userChats []models.UserChat
+	err := r.db.Where("chat_id = ? AND is_admin", chatId).Order("id").Find(&userChats).Error
+	return userChats, err
+}
+
+// Members in join order after the given row id. An empty role and a nil
+// userIds list everyone.
+func (r *ChatRepository) GetChatMembersPage(chatId uuid.UUID, afterId uint, role string, userIds []uuid.UUID, limit int) ([]models.UserChat, error) {
+	var userChats []models.UserChat
+	query := r.db.Where("chat_id = ? AND id > ?", chatId, afterId)
+	switch role {
+	case models.MemberRoleAdmin:
+		query = query.Where("is_admin")
+	case models.MemberRoleMember:
+		query = query.Where("NOT is_admin AND NOT read_only")
+	case models.MemberRoleReader:
+		query = query.Where("read_only AND NOT is_admin")
+	}
+	if userIds != nil {
+		query = query.Where("user_id IN (?)", userIds)
+	}
+	err := query.Order("id").Limit(limit).Find(&userChats).Error
+	return userChats, err
+}
+
 func (r *ChatRepository) GetChatsForUser(userId uuid.UUID) ([]models.UserChat, error) {
 	var userChats []models.UserChat
 	err := r.db.Where("user_id = ?", userId).Find(&userChats).Error
 	return userChats, err
 }
+
+// The other member of each of the given direct chats.
+func (r *ChatRepository) FindDirectPeers(chatIds []uuid.UUID, userId uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	peers := make(map[uuid.UUID]uuid.UUID, len(chatIds))
+	if len(chatIds) == 0 {
+		return peers, nil
+	}
+	var userChats []models.UserChat
+	err := r.db.Where("chat_id IN (?) AND user_id <> ?", chatIds, userId).Find(&userChats).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, userChat := range userChats {
+		peers[userChat.ChatId] = userChat.UserId
+	}
+	return peers, nil
+}
+
+// Admins first, then members before readers, longest-serving first.
+func (r *ChatRepository) FindSuccessor(chatId uuid.UUID, ownerId uuid.UUID) (*models.UserChat, error) {
+	var userChat models.UserChat
+	err := r.db.
+		Where("chat_id = ? AND user_id <> ?", chatId, ownerId).
+		Order("is_admin desc, read_only asc, created_at asc").
+		First(&userChat).Error
+	if err != nil {
+		return nil, err
+	}
+	return &userChat, nil
+}
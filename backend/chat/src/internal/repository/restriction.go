@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+func (r *ChatRepository) SaveRestriction(restriction *models.ChatRestriction) error {
+	return r.db.Save(restriction).Error
+}
+
+func (r *ChatRepository) FindActiveRestriction(chatId uuid.UUID, userId uuid.UUID, kind string, now time.Time) (*models.ChatRestriction, error) {
+	var restriction models.ChatRestriction
+	err := r.db.
+		Where("chat_id = ? AND user_id = ? AND kind = ? AND lifted_at IS NULL", chatId, userId, kind).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		First(&restriction).Error
+	if err != nil {
+		return nil, err
+	}
+	return &restriction, nil
+}
+
+func (r *ChatRepository) GetActiveRestrictions(chatId uuid.UUID, now time.Time) ([]models.ChatRestriction, error) {
+	var restrictions []models.ChatRestriction
+	err := r.db.
+		Where("chat_id = ? AND lifted_at IS NULL", chatId).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("created_at asc").
+		Find(&restrictions).Error
+	return restrictions, err
+}
+
+func (r *ChatRepository) GetExpiredRestrictions(now time.Time, limit int) ([]models.ChatRestriction, error) {
+	var restrictions []models.ChatRestriction
+	err := r.db.
+		Where("lifted_at IS NULL AND expires_at <= ?", now).
+		Order("expires_at asc").
+		Limit(limit).
+		Find(&restrictions).Error
+	return restrictions, err
+}
+
+// Reports false when another instance already lifted it.
+func (r *ChatRepository) LiftRestriction(restrictionId uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.Model(&models.ChatRestriction{}).
+		Where("id = ? AND lifted_at IS NULL", restrictionId).
+		UpdateColumn("lifted_at", now)
+	return result.RowsAffected > 0, result.Error
+}
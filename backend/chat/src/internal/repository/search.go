@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+type MessageSearchFilter struct {
+	ChatIds       []uuid.UUID
+	ViewerId      uuid.UUID
+	Query         string
+	SenderId      *uuid.UUID
+	From          *time.Time
+	To            *time.Time
+	HasAttachment *bool
+	// Keyset position, only results ranked after it are returned.
+	After *MessageSearchPosition
+	Limit int
+}
+
+type MessageSearchPosition struct {
+	Rank      float64
+	CreatedAt time.Time
+	Id        uuid.UUID
+}
+
+type MessageSearchRow struct {
+	models.Message
+	Rank    float64
+	Snippet string
+}
+
+func (r *ChatRepository) IndexMessage(messageId uuid.UUID, language string) error {
+	return r.db.Exec("UPDATE messages SET search_vector = to_tsvector(?::regconfig, coalesce(text, '')) WHERE id = ?", language, messageId).Error
+}
+
+// Reindexes messages with ids greater than afterId and returns the last id of
+// the batch, or uuid.Nil once everything is indexed.
+func (r *ChatRepository) ReindexMessages(afterId uuid.UUID, batchSize int, language string) (uuid.UUID, int, error) {
+	var ids []uuid.UUID
+	err := r.db.Table("messages").Where("id > ?", afterId).Order("id asc").Limit(batchSize).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return uuid.Nil, 0, err
+	}
+	err = r.db.Exec("UPDATE messages SET search_vector = to_tsvector(?::regconfig, coalesce(text, '')) WHERE id IN (?)", language, ids).Error
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	return ids[len(ids)-1], len(ids), nil
+}
+
+func (r *ChatRepository) SearchMessages(filter *MessageSearchFilter, language string) ([]MessageSearchRow, error) {
+	var rows []MessageSearchRow
+	if len(filter.ChatIds) == 0 {
+		return rows, nil
+	}
+	// Matches are marked with control characters stripped from the text
+	// itself, the service escapes the snippet before turning them into markup.
+	query := r.db.Table("messages").
+		Select(`messages.*,
+			ts_rank(search_vector, websearch_to_tsquery(?::regconfig, ?))::float8 AS rank,
+			ts_headline(?::regconfig, translate(coalesce(text, ''), E'\x01\x02', ''), websearch_to_tsquery(?::regconfig, ?),
+				'StartSel="'||E'\x01'||'", StopSel="'||E'\x02'||'", MaxFragments=2') AS snippet`,
+			language, filter.Query, language, language, filter.Query).
+		Where("deleted_at IS NULL").
+		Where("chat_id IN (?)", filter.ChatIds).
+		Where("search_vector @@ websearch_to_tsquery(?::regconfig, ?)", language, filter.Query).
+		Where("NOT EXISTS (SELECT 1 FROM message_deletions md WHERE md.message_id = messages.id AND md.user_id = ?)", filter.ViewerId)
+	if filter.SenderId != nil {
+		query = query.Where("sender_id = ?", *filter.SenderId)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.HasAttachment != nil {
+		if *filter.HasAttachment {
+			query = query.Where("cardinality(coalesce(attachments, '{}')) > 0")
+		} else {
+			query = query.Where("cardinality(coalesce(attachments, '{}')) = 0")
+		}
+	}
+	if filter.After != nil {
+		query = query.Where("(ts_rank(search_vector, websearch_to_tsquery(?::regconfig, ?))::float8, created_at, id) < (?, ?, ?)",
+			language, filter.Query, filter.After.Rank, filter.After.CreatedAt, filter.After.Id)
+	}
+	err := query.Order("rank desc, created_at desc, id desc").Limit(filter.Limit).Scan(&rows).Error
+	return rows, err
+}
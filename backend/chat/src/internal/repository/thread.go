@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+type threadCount struct {
+	ThreadRootId uuid.UUID
+	Count        int
+}
+
+func (r *ChatRepository) CountThreadReplies(rootIds []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(rootIds) == 0 {
+		return counts, nil
+	}
+	var rows []threadCount
+	err := r.db.Model(&models.Message{}).
+		Select("thread_root_id, count(*) AS count").
+		Where("thread_root_id IN (?)", rootIds).
+		Group("thread_root_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ThreadRootId] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *ChatRepository) CountThreads(chatId uuid.UUID) (int, error) {
+	var count int
+	err := r.db.Model(&models.Message{}).
+		Where("chat_id = ? AND thread_root_id IS NOT NULL", chatId).
+		Select("count(DISTINCT thread_root_id)").
+		Count(&count).Error
+	return count, err
+}
+
+type chatThreads struct {
+	ChatId uuid.UUID
+	Count  int
+}
+
+func (r *ChatRepository) CountChatThreads(chatIds []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(chatIds) == 0 {
+		return counts, nil
+	}
+	var rows []chatThreads
+	err := r.db.Model(&models.Message{}).
+		Select("chat_id, count(DISTINCT thread_root_id) AS count").
+		Where("chat_id IN (?) AND thread_root_id IS NOT NULL", chatIds).
+		Group("chat_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ChatId] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *ChatRepository) FindThreadFollower(rootId uuid.UUID, userId uuid.UUID) (*models.ThreadFollower, error) {
+	var follower models.ThreadFollower
+	err := r.db.Where("root_message_id = ? AND user_id = ?", rootId, userId).First(&follower).Error
+	if err != nil {
+		return nil, err
+	}
+	return &follower, nil
+}
+
+func (r *ChatRepository) SaveThreadFollower(follower *models.ThreadFollower) error {
+	return r.db.Save(follower).Error
+}
+
+func (r *ChatRepository) GetFollowedThreads(chatId uuid.UUID, userId uuid.UUID) ([]models.ThreadFollower, error) {
+	var followers []models.ThreadFollower
+	err := r.db.Where("chat_id = ? AND user_id = ? AND following", chatId, userId).Find(&followers).Error
+	return followers, err
+}
+
+// Counts per thread what the user has not read since their read position in it.
+func (r *ChatRepository) CountUnreadThreadMessages(rootIds []uuid.UUID, userId uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(rootIds) == 0 {
+		return counts, nil
+	}
+	var rows []threadCount
+	err := r.db.Raw(
+		`SELECT m.thread_root_id, count(*) AS count FROM messages m
+		JOIN thread_followers tf ON tf.root_message_id = m.thread_root_id AND tf.user_id = ?
+		WHERE m.thread_root_id IN (?) AND m.deleted_at IS NULL AND m.sender_id <> ? AND m.created_at > tf.last_read_at
+		GROUP BY m.thread_root_id`,
+		userId, rootIds, userId,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ThreadRootId] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+// Runs fn against a repository bound to one database transaction. The
+// transaction commits when fn returns nil and rolls back otherwise.
+func (r *ChatRepository) Transaction(fn func(tx *ChatRepository) error) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+	err := fn(&ChatRepository{db: tx, redis: r.redis})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// Serializes membership changes of one chat until the transaction ends.
+func (r *ChatRepository) LockChat(chatId uuid.UUID) (*models.Chat, error) {
+	var chat models.Chat
+	err := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", chatId).First(&chat).Error
+	if err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+func (r *ChatRepository) CountChatMembers(chatId uuid.UUID) (int, error) {
+	var count int
+	err := r.db.Model(&models.UserChat{}).Where("chat_id = ?", chatId).Count(&count).Error
+	return count, err
+}
+
+// Reports false when the user already is a member.
+func (r *ChatRepository) InsertUserChat(userChat *models.UserChat) (bool, error) {
+	result := r.db.Exec(
+		`INSERT INTO user_chats (created_at, updated_at, chat_id, user_id, read_only, is_admin, admin_rights, invited_by)
+		VALUES (now(), now(), ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (chat_id, user_id) WHERE deleted_at IS NULL DO NOTHING`,
+		userChat.ChatId, userChat.UserId, userChat.ReadOnly, userChat.IsAdmin, userChat.AdminRights, userChat.InvitedBy,
+	)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ChatRepository) LockJoinRequest(chatId uuid.UUID, requestId uuid.UUID) (*models.JoinRequest, error) {
+	var request models.JoinRequest
+	err := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ? AND chat_id = ?", requestId, chatId).First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *ChatRepository) LockInviteLink(chatId uuid.UUID, linkId uuid.UUID) (*models.InviteLink, error) {
+	var link models.InviteLink
+	err := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ? AND chat_id = ?", linkId, chatId).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
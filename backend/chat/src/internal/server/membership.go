@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+
+	chat_mgmt "github.com/PolyTechProjects/chaotic_chat/chat/src/gen/go/chat"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/service"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Other services call with these metadata keys instead of a user's access
+// token. Tokens are configured per service name.
+const (
+	serviceNameKey  = "x-service-name"
+	serviceTokenKey = "x-service-token"
+)
+
+var membershipEvents = map[string]bool{
+	dto.EventChatCreated:    true,
+	dto.EventChatDeleted:    true,
+	dto.EventMembersAdded:   true,
+	dto.EventMembersRemoved: true,
+	dto.EventMembersUpdated: true,
+	dto.EventMemberBanned:   true,
+	dto.EventMemberUnbanned: true,
+	dto.EventMemberMuted:    true,
+	dto.EventMemberUnmuted:  true,
+	dto.EventOwnerChanged:   true,
+}
+
+func hasServiceCredentials(ctx context.Context) bool {
+	return len(metadata.ValueFromIncomingContext(ctx, serviceNameKey)) > 0
+}
+
+func (s *GRPCServer) authorizeService(ctx context.Context) (string, error) {
+	names := metadata.ValueFromIncomingContext(ctx, serviceNameKey)
+	tokens := metadata.ValueFromIncomingContext(ctx, serviceTokenKey)
+	if len(names) == 0 || len(tokens) == 0 {
+		return "", status.Error(codes.Unauthenticated, "service credentials are missing")
+	}
+	expected, ok := s.serviceTokens[names[0]]
+	if !ok || expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(tokens[0])) != 1 {
+		slog.Error("Invalid service credentials", "service", names[0])
+		return "", status.Error(codes.Unauthenticated, "service credentials are invalid")
+	}
+	return names[0], nil
+}
+
+func (s *GRPCServer) IsMember(ctx context.Context, req *chat_mgmt.IsMemberRequest) (*chat_mgmt.IsMemberResponse, error) {
+	_, err := s.authorizeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chatId, userId, err := parseChatAndUser(req.ChatId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.service.GetMemberRole(chatId, userId)
+	if errors.Is(err, service.ErrNotMember) {
+		return &chat_mgmt.IsMemberResponse{}, nil
+	}
+	if err != nil {
+		slog.Error("IsMember error", "error", err.Error())
+		return nil, grpcError(err)
+	}
+	return &chat_mgmt.IsMemberResponse{IsMember: true, Role: role}, nil
+}
+
+func (s *GRPCServer) ListChatsForUser(ctx context.Context, req *chat_mgmt.ListChatsForUserRequest) (*chat_mgmt.ListChatsForUserResponse, error) {
+	_, err := s.authorizeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	memberships, err := s.service.GetMembershipsForUser(userId)
+	if err != nil {
+		slog.Error("ListChatsForUser error", "error", err.Error())
+		return nil, grpcError(err)
+	}
+	resp := &chat_mgmt.ListChatsForUserResponse{UserId: userId.String()}
+	for _, membership := range memberships {
+		resp.Chats = append(resp.Chats, &chat_mgmt.ChatMembership{
+			ChatId:    membership.Chat.Id.String(),
+			Name:      membership.Chat.Name,
+			IsChannel: membership.Chat.IsChannel,
+			IsDirect:  membership.Chat.IsDirect,
+			Role:      membership.Role,
+		})
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) GetMemberPermissions(ctx context.Context, req *chat_mgmt.GetMemberPermissionsRequest) (*chat_mgmt.MemberPermissionsResponse, error) {
+	_, err := s.authorizeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chatId, userId, err := parseChatAndUser(req.ChatId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.service.GetMemberPermissions(chatId, userId)
+	if err != nil {
+		slog.Error("GetMemberPermissions error", "error", err.Error())
+		return nil, grpcError(err)
+	}
+	return &chat_mgmt.MemberPermissionsResponse{
+		Role:        permissions.Role,
+		Permissions: int32(permissions.Permissions),
+		AdminRights: int32(permissions.AdminRights),
+	}, nil
+}
+
+// Streams membership changes until the caller goes away. Events are best
+// effort like every chat event, a watcher that reconnects should resync.
+func (s *GRPCServer) WatchMembership(req *chat_mgmt.WatchMembershipRequest, stream chat_mgmt.ChatManagement_WatchMembershipServer) error {
+	ctx := stream.Context()
+	name, err := s.authorizeService(ctx)
+	if err != nil {
+		return err
+	}
+	for _, chatId := range req.ChatIds {
+		_, err = uuid.Parse(chatId)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	slog.Info("Membership watch started", "service", name)
+	pubsub := s.service.SubscribeChatEvents(ctx)
+	defer pubsub.Close()
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return status.Error(codes.Unavailable, "chat events are unavailable")
+			}
+			var event dto.ChatEvent
+			err = json.Unmarshal([]byte(msg.Payload), &event)
+			if err != nil {
+				slog.Error("Failed to decode chat event", "error", err.Error())
+				continue
+			}
+			if !membershipEvents[event.Type] {
+				continue
+			}
+			if len(req.ChatIds) > 0 && !slices.Contains(req.ChatIds, event.ChatId.String()) {
+				continue
+			}
+			resp := &chat_mgmt.MembershipEvent{Type: event.Type, ChatId: event.ChatId.String()}
+			for _, userId := range event.UserIds {
+				resp.UserIds = append(resp.UserIds, userId.String())
+			}
+			err = stream.Send(resp)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func parseChatAndUser(rawChatId string, rawUserId string) (uuid.UUID, uuid.UUID, error) {
+	chatId, err := uuid.Parse(rawChatId)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return chatId, userId, nil
+}
@@ -2,16 +2,21 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"time"
 
 	chat_mgmt "github.com/PolyTechProjects/chaotic_chat/chat/src/gen/go/chat"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/client"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/controller"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/gateway"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/service"
 	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -19,14 +24,18 @@ import (
 
 type HttpServer struct {
 	chatMgmtController *controller.ChatManagementController
+	gateway            *gateway.Gateway
 }
 
-func NewHttpServer(chatMgmtController *controller.ChatManagementController) *HttpServer {
-	return &HttpServer{chatMgmtController: chatMgmtController}
+func NewHttpServer(chatMgmtController *controller.ChatManagementController, gateway *gateway.Gateway) *HttpServer {
+	return &HttpServer{chatMgmtController: chatMgmtController, gateway: gateway}
 }
 
 func (h *HttpServer) StartServer() {
 	http.HandleFunc("GET /chat", h.chatMgmtController.GetAllAvailableChatsHandler)
+	http.HandleFunc("GET /chat/ws", h.gateway.HandleConnection)
+	http.HandleFunc("POST /chat/direct", h.chatMgmtController.GetOrCreateDirectChatHandler)
+	http.HandleFunc("GET /chat/search", h.chatMgmtController.SearchMessagesHandler)
 	http.HandleFunc("POST /chat/room", h.chatMgmtController.CreateChatHandler)
 	http.HandleFunc("DELETE /chat/room", h.chatMgmtController.DeleteChatHandler)
 	http.HandleFunc("GET /chat/room", h.chatMgmtController.GetChatHandler)
@@ -37,22 +46,68 @@ func (h *HttpServer) StartServer() {
 	http.HandleFunc("PUT /chat/room/admins", h.chatMgmtController.AddAdminsInChatHandler)
 	http.HandleFunc("DELETE /chat/room/readers", h.chatMgmtController.MakeReadersUsersInChatHandler)
 	http.HandleFunc("PUT /chat/room/readers", h.chatMgmtController.MakeUsersReadersInChatHandler)
+	http.HandleFunc("GET /chat/room/messages", h.chatMgmtController.GetMessagesHandler)
+	http.HandleFunc("POST /chat/room/messages", h.chatMgmtController.SendMessageHandler)
+	http.HandleFunc("PUT /chat/room/messages", h.chatMgmtController.EditMessageHandler)
+	http.HandleFunc("DELETE /chat/room/messages", h.chatMgmtController.DeleteMessageHandler)
+	http.HandleFunc("GET /chat/room/messages/edits", h.chatMgmtController.GetMessageEditsHandler)
+	http.HandleFunc("GET /chat/room/messages/reactions", h.chatMgmtController.GetReactionsHandler)
+	http.HandleFunc("PUT /chat/room/messages/reactions", h.chatMgmtController.AddReactionHandler)
+	http.HandleFunc("DELETE /chat/room/messages/reactions", h.chatMgmtController.RemoveReactionHandler)
+	http.HandleFunc("GET /chat/room/messages/search", h.chatMgmtController.SearchChatMessagesHandler)
+	http.HandleFunc("GET /chat/room/messages/reads", h.chatMgmtController.GetMessageReadsHandler)
+	http.HandleFunc("POST /chat/room/read", h.chatMgmtController.MarkReadHandler)
+	http.HandleFunc("POST /chat/room/signals", h.chatMgmtController.EmitSignalHandler)
+	http.HandleFunc("GET /chat/room/threads", h.chatMgmtController.GetThreadMessagesHandler)
+	http.HandleFunc("GET /chat/room/threads/followed", h.chatMgmtController.GetFollowedThreadsHandler)
+	http.HandleFunc("PUT /chat/room/threads/follow", h.chatMgmtController.FollowThreadHandler)
+	http.HandleFunc("DELETE /chat/room/threads/follow", h.chatMgmtController.UnfollowThreadHandler)
+	http.HandleFunc("GET /chat/room/invites", h.chatMgmtController.GetInviteLinksHandler)
+	http.HandleFunc("POST /chat/room/invites", h.chatMgmtController.CreateInviteLinkHandler)
+	http.HandleFunc("DELETE /chat/room/invites", h.chatMgmtController.RevokeInviteLinkHandler)
+	http.HandleFunc("POST /chat/room/invites/rotate", h.chatMgmtController.RotateInviteLinkHandler)
+	http.HandleFunc("GET /chat/room/invites/joins", h.chatMgmtController.GetInviteJoinsHandler)
+	http.HandleFunc("GET /chat/room/requests", h.chatMgmtController.GetJoinRequestsHandler)
+	http.HandleFunc("POST /chat/room/requests/approve", h.chatMgmtController.ApproveJoinRequestHandler)
+	http.HandleFunc("POST /chat/room/requests/reject", h.chatMgmtController.RejectJoinRequestHandler)
+	http.HandleFunc("GET /chat/room/restrictions", h.chatMgmtController.GetRestrictionsHandler)
+	http.HandleFunc("POST /chat/room/bans", h.chatMgmtController.BanMemberHandler)
+	http.HandleFunc("DELETE /chat/room/bans", h.chatMgmtController.UnbanMemberHandler)
+	http.HandleFunc("POST /chat/room/mutes", h.chatMgmtController.MuteMemberHandler)
+	http.HandleFunc("DELETE /chat/room/mutes", h.chatMgmtController.UnmuteMemberHandler)
+	http.HandleFunc("POST /chat/room/owner", h.chatMgmtController.TransferOwnershipHandler)
+	http.HandleFunc("POST /chat/room/owner/confirm", h.chatMgmtController.ConfirmOwnershipHandler)
+	http.HandleFunc("DELETE /chat/room/owner", h.chatMgmtController.CancelOwnershipTransferHandler)
+	http.HandleFunc("POST /chat/room/leave", h.chatMgmtController.LeaveChatHandler)
+	http.HandleFunc("GET /chat/room/members", h.chatMgmtController.GetMembersHandler)
+	http.HandleFunc("POST /chat/room/upgrade", h.chatMgmtController.UpgradeToSupergroupHandler)
+	http.HandleFunc("GET /chat/room/pins", h.chatMgmtController.GetPinsHandler)
+	http.HandleFunc("POST /chat/room/pins", h.chatMgmtController.PinMessageHandler)
+	http.HandleFunc("DELETE /chat/room/pins", h.chatMgmtController.UnpinMessageHandler)
+	http.HandleFunc("DELETE /chat/room/pins/all", h.chatMgmtController.UnpinAllMessagesHandler)
+	http.HandleFunc("POST /chat/room/polls", h.chatMgmtController.SendPollHandler)
+	http.HandleFunc("POST /chat/room/polls/close", h.chatMgmtController.ClosePollHandler)
+	http.HandleFunc("GET /chat/room/polls/votes", h.chatMgmtController.GetPollVotersHandler)
+	http.HandleFunc("PUT /chat/room/polls/votes", h.chatMgmtController.VoteHandler)
+	http.HandleFunc("DELETE /chat/room/polls/votes", h.chatMgmtController.RetractVoteHandler)
 	http.HandleFunc("POST /chat/room/{joinLink}", h.chatMgmtController.JoinChatHandler)
 }
 
 type GRPCServer struct {
 	gRPCServer *grpc.Server
 	chat_mgmt.UnimplementedChatManagementServer
-	service    *service.ChatManagementService
-	authClient *client.AuthGRPCClient
+	service       *service.ChatManagementService
+	authClient    *client.AuthGRPCClient
+	serviceTokens map[string]string
 }
 
-func New(service *service.ChatManagementService, authClient *client.AuthGRPCClient) *GRPCServer {
+func New(service *service.ChatManagementService, authClient *client.AuthGRPCClient, serviceTokens map[string]string) *GRPCServer {
 	gRPCServer := grpc.NewServer()
 	g := &GRPCServer{
-		gRPCServer: gRPCServer,
-		service:    service,
-		authClient: authClient,
+		gRPCServer:    gRPCServer,
+		service:       service,
+		authClient:    authClient,
+		serviceTokens: serviceTokens,
 	}
 	chat_mgmt.RegisterChatManagementServer(gRPCServer, g)
 	return g
@@ -66,7 +121,12 @@ func (s *GRPCServer) Start(l net.Listener) error {
 
 func (s *GRPCServer) GetChat(ctx context.Context, req *chat_mgmt.GetChatRequest) (*chat_mgmt.ChatRoomResponse, error) {
 	slog.Info("GetChat controller started")
-	_, err := s.authClient.PerformAuthorize(ctx, nil)
+	var err error
+	if hasServiceCredentials(ctx) {
+		_, err = s.authorizeService(ctx)
+	} else {
+		_, err = s.authClient.PerformAuthorize(ctx, nil)
+	}
 	if err != nil {
 		slog.Error(fmt.Sprintf("Authorization error: %v", err.Error()))
 		return nil, err
@@ -80,7 +140,7 @@ func (s *GRPCServer) GetChat(ctx context.Context, req *chat_mgmt.GetChatRequest)
 	chat, err := s.service.GetChat(chatId)
 	if err != nil {
 		slog.Error("GetChat error", "error", err.Error())
-		return nil, err
+		return nil, grpcError(err)
 	}
 	return &chat_mgmt.ChatRoomResponse{
 		ChatId:          chat.Chat.Id.String(),
@@ -90,5 +150,134 @@ func (s *GRPCServer) GetChat(ctx context.Context, req *chat_mgmt.GetChatRequest)
 		ParticipantsIds: chat.Users,
 		AdminsIds:       chat.Admins,
 		ReadersIds:      chat.Readers,
+		MembersCount:    int64(chat.MembersCount),
 	}, nil
 }
+
+func (s *GRPCServer) DeleteChat(ctx context.Context, req *chat_mgmt.DeleteChatRequest) (*chat_mgmt.DeleteChatResponse, error) {
+	authResp, err := s.authClient.PerformAuthorize(ctx, nil)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Authorization error: %v", err.Error()))
+		return nil, err
+	}
+	if !canModerate(authResp.Role) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	chatId, err := uuid.Parse(req.ChatId)
+	if err != nil {
+		slog.Error("Invalid chat Id", "error", err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err = s.service.DeleteChat(chatId)
+	if err != nil {
+		slog.Error("DeleteChat error", "error", err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	slog.Info("Chat deleted by moderator", "chatId", chatId, "moderatorId", authResp.UserId)
+	return &chat_mgmt.DeleteChatResponse{ChatId: chatId.String()}, nil
+}
+
+// Called by user_mgmt when an account is deleted, on behalf of the account
+// owner or an admin.
+func (s *GRPCServer) LeaveAllChats(ctx context.Context, req *chat_mgmt.LeaveAllChatsRequest) (*chat_mgmt.LeaveAllChatsResponse, error) {
+	authResp, err := s.authClient.PerformAuthorize(ctx, nil)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Authorization error: %v", err.Error()))
+		return nil, err
+	}
+	if authResp.UserId != req.UserId && authResp.Role != "admin" {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		slog.Error("Invalid user Id", "error", err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	chatIds, err := s.service.LeaveAllChats(userId)
+	if err != nil {
+		slog.Error("LeaveAllChats error", "error", err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &chat_mgmt.LeaveAllChatsResponse{UserId: userId.String()}
+	for _, chatId := range chatIds {
+		resp.ChatIds = append(resp.ChatIds, chatId.String())
+	}
+	return resp, nil
+}
+
+// Services list any chat, users only the chats they belong to.
+func (s *GRPCServer) ListMembers(ctx context.Context, req *chat_mgmt.ListMembersRequest) (*chat_mgmt.ListMembersResponse, error) {
+	viewerId := uuid.Nil
+	if hasServiceCredentials(ctx) {
+		_, err := s.authorizeService(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		authResp, err := s.authClient.PerformAuthorize(ctx, nil)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Authorization error: %v", err.Error()))
+			return nil, err
+		}
+		viewerId, err = uuid.Parse(authResp.UserId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	chatId, err := uuid.Parse(req.ChatId)
+	if err != nil {
+		slog.Error("Invalid chat Id", "error", err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	query := &dto.MembersQuery{Cursor: req.Cursor, Limit: int(req.Limit), Role: req.Role}
+	for _, rawId := range req.UserIds {
+		userId, err := uuid.Parse(rawId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		query.UserIds = append(query.UserIds, userId)
+	}
+	var page *dto.MembersPageResponse
+	if viewerId == uuid.Nil {
+		page, err = s.service.ListMembers(chatId, query)
+	} else {
+		page, err = s.service.GetMembers(chatId, viewerId, query)
+	}
+	if err != nil {
+		slog.Error("ListMembers error", "error", err.Error())
+		return nil, grpcError(err)
+	}
+	resp := &chat_mgmt.ListMembersResponse{NextCursor: page.NextCursor}
+	for _, member := range page.Members {
+		invitedBy := ""
+		if member.InvitedBy != nil {
+			invitedBy = member.InvitedBy.String()
+		}
+		resp.Members = append(resp.Members, &chat_mgmt.ChatMember{
+			UserId:      member.UserId.String(),
+			Role:        member.Role,
+			AdminRights: int32(member.AdminRights),
+			JoinedAt:    member.JoinedAt.Format(time.RFC3339),
+			InvitedBy:   invitedBy,
+		})
+	}
+	return resp, nil
+}
+
+func grpcError(err error) error {
+	switch {
+	case gorm.IsRecordNotFoundError(err):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrNotMember):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrInvalidMemberRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func canModerate(role string) bool {
+	return role == "moderator" || role == "admin"
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+const directChatName = "Direct"
+
+var (
+	ErrDirectChat        = errors.New("operation is not available in a direct chat")
+	ErrDirectWithSelf    = errors.New("cannot start a direct chat with yourself")
+	ErrDirectChatBlocked = errors.New("user does not accept direct messages from you")
+)
+
+// The join link of a direct chat is derived from the ordered pair of user ids.
+// Its unique index guarantees one conversation per pair, and JoinChat refuses
+// such links.
+func directChatKey(userId uuid.UUID, peerId uuid.UUID) string {
+	first, second := userId.String(), peerId.String()
+	if second < first {
+		first, second = second, first
+	}
+	return "direct:" + first + ":" + second
+}
+
+func (s *ChatManagementService) GetOrCreateDirectChat(userId uuid.UUID, peerId uuid.UUID) (*dto.GetChatResponse, error) {
+	if userId == peerId {
+		return nil, ErrDirectWithSelf
+	}
+	err := s.checkNotBlocked(userId, peerId)
+	if err != nil {
+		return nil, err
+	}
+	key := directChatKey(userId, peerId)
+	chat, err := s.repo.FindByJoinLink(key)
+	if err == nil {
+		return s.GetChat(chat.Id)
+	}
+	if !gorm.IsRecordNotFoundError(err) {
+		slog.Error("Failed to find direct chat", "error", err.Error())
+		return nil, err
+	}
+
+	chat = &models.Chat{
+		Id:        uuid.New(),
+		Name:      directChatName,
+		CreatorId: userId,
+		IsDirect:  true,
+		JoinLink:  key,
+	}
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		err := tx.SaveChat(chat)
+		if err != nil {
+			return err
+		}
+		for _, memberId := range []uuid.UUID{userId, peerId} {
+			_, err = tx.InsertUserChat(&models.UserChat{ChatId: chat.Id, UserId: memberId})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		chat, err = s.repo.FindByJoinLink(key)
+		if err != nil {
+			return nil, err
+		}
+		return s.GetChat(chat.Id)
+	}
+	if err != nil {
+		slog.Error("Failed to create direct chat", "error", err.Error())
+		return nil, err
+	}
+	s.publishEvent(dto.EventChatCreated, chat.Id, []uuid.UUID{userId, peerId}, chat)
+	return s.GetChat(chat.Id)
+}
+
+// There is no block list yet. Direct chats consult it here once one exists.
+func (s *ChatManagementService) checkNotBlocked(userId uuid.UUID, peerId uuid.UUID) error {
+	return nil
+}
+
+func (s *ChatManagementService) GetDirectPeers(chatIds []uuid.UUID, userId uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	return s.repo.FindDirectPeers(chatIds, userId)
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Events are best effort: a failed publish is logged and never fails the
+// operation that produced it.
+func (s *ChatManagementService) publishEvent(eventType string, chatId uuid.UUID, userIds []uuid.UUID, payload any) {
+	event := &dto.ChatEvent{Type: eventType, ChatId: chatId, UserIds: userIds, Payload: payload}
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to marshal chat event", "error", err.Error())
+		return
+	}
+	err = s.repo.PublishInChatEventsChannel(bytes)
+	if err != nil {
+		slog.Error("Failed to publish chat event", "error", err.Error(), "type", eventType)
+	}
+}
+
+func (s *ChatManagementService) SubscribeChatEvents(ctx context.Context) *redis.PubSub {
+	return s.repo.SubscribeChatEventsChannel(ctx)
+}
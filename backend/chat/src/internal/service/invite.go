@@ -0,0 +1,332 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrInviteRevoked      = errors.New("invite link is revoked")
+	ErrInviteExpired      = errors.New("invite link is expired")
+	ErrInviteExhausted    = errors.New("invite link has no uses left")
+	ErrJoinRequestDecided = errors.New("join request is already decided")
+	ErrChatFull           = errors.New("chat is full")
+)
+
+const PrimaryInviteLinkName = "Primary"
+
+func newInviteToken() string {
+	raw := make([]byte, 16)
+	_, err := rand.Read(raw)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func (s *ChatManagementService) requireChatAdmin(chatId uuid.UUID, userId uuid.UUID) (*models.Chat, error) {
+	chat, _, err := s.authorizeIn(chatId, userId, ActionManageChat)
+	return chat, err
+}
+
+func (s *ChatManagementService) CreateInviteLink(userId uuid.UUID, req *dto.CreateInviteLinkRequest) (*models.InviteLink, error) {
+	chat, err := s.requireChatAdmin(req.ChatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if chat.IsDirect {
+		return nil, ErrDirectChat
+	}
+	link := &models.InviteLink{
+		Id:               uuid.New(),
+		ChatId:           chat.Id,
+		Token:            newInviteToken(),
+		Name:             req.Name,
+		CreatorId:        userId,
+		ExpiresAt:        req.ExpiresAt,
+		MaxUses:          req.MaxUses,
+		RequiresApproval: req.RequiresApproval,
+	}
+	err = s.repo.SaveInviteLink(link)
+	if err != nil {
+		slog.Error("Failed to save invite link", "error", err.Error())
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *ChatManagementService) GetInviteLinks(chatId uuid.UUID, userId uuid.UUID) ([]models.InviteLink, error) {
+	_, err := s.requireChatAdmin(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetInviteLinks(chatId)
+}
+
+func (s *ChatManagementService) RevokeInviteLink(chatId uuid.UUID, linkId uuid.UUID, userId uuid.UUID) error {
+	_, err := s.requireChatAdmin(chatId, userId)
+	if err != nil {
+		return err
+	}
+	link, err := s.repo.FindInviteLink(chatId, linkId)
+	if err != nil {
+		return err
+	}
+	if link.RevokedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	link.RevokedAt = &now
+	err = s.repo.SaveInviteLink(link)
+	if err != nil {
+		slog.Error("Failed to revoke invite link", "error", err.Error())
+	}
+	return err
+}
+
+// Revokes the link and issues a fresh token with the same settings. The
+// expiry is not carried over, the new link never expires. Rotating the
+// primary link also replaces the chat's join link.
+func (s *ChatManagementService) RotateInviteLink(chatId uuid.UUID, linkId uuid.UUID, userId uuid.UUID) (*models.InviteLink, error) {
+	_, err := s.requireChatAdmin(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	var link *models.InviteLink
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		chat, err := tx.LockChat(chatId)
+		if err != nil {
+			return err
+		}
+		old, err := tx.LockInviteLink(chatId, linkId)
+		if err != nil {
+			return err
+		}
+		if old.RevokedAt != nil {
+			return ErrInviteRevoked
+		}
+		now := time.Now()
+		old.RevokedAt = &now
+		err = tx.SaveInviteLink(old)
+		if err != nil {
+			return err
+		}
+		link = &models.InviteLink{
+			Id:               uuid.New(),
+			ChatId:           chatId,
+			Token:            newInviteToken(),
+			Name:             old.Name,
+			CreatorId:        userId,
+			MaxUses:          old.MaxUses,
+			RequiresApproval: old.RequiresApproval,
+		}
+		err = tx.SaveInviteLink(link)
+		if err != nil {
+			return err
+		}
+		if chat.JoinLink != old.Token {
+			return nil
+		}
+		chat.JoinLink = link.Token
+		return tx.UpdateChat(chat)
+	})
+	if err != nil {
+		slog.Error("Failed to rotate invite link", "error", err.Error())
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *ChatManagementService) GetInviteJoins(chatId uuid.UUID, userId uuid.UUID, linkId *uuid.UUID) ([]models.InviteJoin, error) {
+	_, err := s.requireChatAdmin(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetInviteJoins(chatId, linkId)
+}
+
+func (s *ChatManagementService) GetJoinRequests(chatId uuid.UUID, userId uuid.UUID) ([]models.JoinRequest, error) {
+	_, err := s.requireChatAdmin(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetPendingJoinRequests(chatId)
+}
+
+func (s *ChatManagementService) DecideJoinRequest(chatId uuid.UUID, requestId uuid.UUID, adminId uuid.UUID, approve bool) (*models.JoinRequest, error) {
+	chat, err := s.requireChatAdmin(chatId, adminId)
+	if err != nil {
+		return nil, err
+	}
+	var request *models.JoinRequest
+	var added []uuid.UUID
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		var err error
+		request, err = tx.LockJoinRequest(chat.Id, requestId)
+		if err != nil {
+			return err
+		}
+		if request.Status != models.JoinRequestPending {
+			return ErrJoinRequestDecided
+		}
+		request.Status = models.JoinRequestRejected
+		if approve {
+			err = s.checkNotBanned(chat.Id, request.UserId)
+			if err != nil {
+				return err
+			}
+			link, err := tx.FindInviteLink(chat.Id, request.InviteLinkId)
+			if err != nil {
+				return err
+			}
+			added, err = s.joinTx(tx, chat.Id, link, request.UserId)
+			if err != nil {
+				return err
+			}
+			request.Status = models.JoinRequestApproved
+		}
+		now := time.Now()
+		request.DecidedBy = &adminId
+		request.DecidedAt = &now
+		return tx.SaveJoinRequest(request)
+	})
+	if err != nil {
+		slog.Error("Failed to decide join request", "error", err.Error())
+		return nil, err
+	}
+	s.publishMembersAdded(chat.Id, added)
+	s.postJoined(chat, added)
+	return request, nil
+}
+
+// Resolves an invite token to its chat. Chats created before managed invite
+// links only have chats.join_link, those resolve without a link.
+func (s *ChatManagementService) resolveInvite(token string) (*models.Chat, *models.InviteLink, error) {
+	link, err := s.repo.FindInviteLinkByToken(token)
+	if err == nil {
+		if link.RevokedAt != nil {
+			return nil, nil, ErrInviteRevoked
+		}
+		if link.IsExpired(time.Now()) {
+			return nil, nil, ErrInviteExpired
+		}
+		chat, err := s.repo.FindById(link.ChatId)
+		if err != nil {
+			return nil, nil, err
+		}
+		return chat, link, nil
+	}
+	if !gorm.IsRecordNotFoundError(err) {
+		return nil, nil, err
+	}
+	chat, err := s.repo.FindByJoinLink(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if chat.IsDirect {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+	return chat, nil, nil
+}
+
+func (s *ChatManagementService) requestToJoin(chat *models.Chat, link *models.InviteLink, userId uuid.UUID) (*models.JoinRequest, error) {
+	request, err := s.repo.FindPendingJoinRequest(chat.Id, userId)
+	if err == nil {
+		return request, nil
+	}
+	if !gorm.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+	if link.MaxUses > 0 && link.Uses >= link.MaxUses {
+		return nil, ErrInviteExhausted
+	}
+	request = &models.JoinRequest{
+		Id:           uuid.New(),
+		ChatId:       chat.Id,
+		UserId:       userId,
+		InviteLinkId: link.Id,
+		Status:       models.JoinRequestPending,
+	}
+	err = s.repo.SaveJoinRequest(request)
+	if err != nil {
+		slog.Error("Failed to save join request", "error", err.Error())
+		return nil, err
+	}
+	userChats, err := s.repo.GetChatUsers(chat.Id)
+	if err != nil {
+		slog.Error("Failed to get chat users", "error", err.Error())
+		return request, nil
+	}
+	var adminIds []uuid.UUID
+	for _, userChat := range userChats {
+		if userChat.IsAdmin {
+			adminIds = append(adminIds, userChat.UserId)
+		}
+	}
+	s.publishEvent(dto.EventJoinRequested, chat.Id, adminIds, request)
+	return request, nil
+}
+
+func (s *ChatManagementService) joinViaInvite(chat *models.Chat, link *models.InviteLink, userId uuid.UUID) error {
+	err := s.checkNotBanned(chat.Id, userId)
+	if err != nil {
+		return err
+	}
+	var added []uuid.UUID
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		var err error
+		added, err = s.joinTx(tx, chat.Id, link, userId)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.publishMembersAdded(chat.Id, added)
+	s.postJoined(chat, added)
+	return nil
+}
+
+// Takes a use of the link only when the user was not a member yet.
+func (s *ChatManagementService) joinTx(tx *repository.ChatRepository, chatId uuid.UUID, link *models.InviteLink, userId uuid.UUID) ([]uuid.UUID, error) {
+	var invitedBy *uuid.UUID
+	if link != nil {
+		invitedBy = &link.CreatorId
+	}
+	_, added, err := s.addMembersTx(tx, chatId, []uuid.UUID{userId}, invitedBy)
+	if err != nil || len(added) == 0 {
+		return nil, err
+	}
+	var linkId *uuid.UUID
+	if link != nil {
+		consumed, err := tx.ConsumeInviteLinkUse(link.Id)
+		if err != nil {
+			slog.Error("Failed to consume invite link use", "error", err.Error())
+			return nil, err
+		}
+		if !consumed {
+			return nil, ErrInviteExhausted
+		}
+		linkId = &link.Id
+	}
+	err = tx.SaveInviteJoin(&models.InviteJoin{
+		Id:           uuid.New(),
+		ChatId:       chatId,
+		UserId:       userId,
+		InviteLinkId: linkId,
+		JoinedAt:     time.Now(),
+	})
+	if err != nil {
+		slog.Error("Failed to record invite join", "error", err.Error())
+		return nil, err
+	}
+	return added, nil
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrChannelUpgrade    = errors.New("channels cannot become supergroups")
+	ErrAlreadySupergroup = errors.New("chat is a supergroup already")
+	ErrInvalidMemberRole = errors.New("unknown member role")
+)
+
+const (
+	DefaultMembersLimit = 50
+	MaxMembersLimit     = 200
+	// A member search looks at this many members per call and stops after
+	// MaxMemberMatches hits.
+	MemberSearchBatch = 1000
+	MaxMemberMatches  = 500
+)
+
+// Member limits per kind of chat, zero means no limit.
+type MemberLimits struct {
+	Group      int
+	Supergroup int
+	Channel    int
+}
+
+func (l MemberLimits) of(chat *models.Chat) int {
+	switch {
+	case chat.IsChannel:
+		return l.Channel
+	case chat.IsSupergroup:
+		return l.Supergroup
+	}
+	return l.Group
+}
+
+// Adds the users that are not members yet and returns them. The chat row
+// stays locked until tx ends, so concurrent joins cannot overshoot the limit.
+func (s *ChatManagementService) addMembersTx(tx *repository.ChatRepository, chatId uuid.UUID, userIds []uuid.UUID, invitedBy *uuid.UUID) (*models.Chat, []uuid.UUID, error) {
+	chat, err := tx.LockChat(chatId)
+	if err != nil {
+		return nil, nil, err
+	}
+	count, err := tx.CountChatMembers(chatId)
+	if err != nil {
+		slog.Error("Failed to count chat members", "error", err.Error())
+		return nil, nil, err
+	}
+	var added []uuid.UUID
+	for _, userId := range userIds {
+		inserted, err := tx.InsertUserChat(&models.UserChat{
+			ChatId:    chatId,
+			UserId:    userId,
+			ReadOnly:  chat.IsChannel,
+			InvitedBy: invitedBy,
+		})
+		if err != nil {
+			slog.Error("Failed to add user to chat", "error", err.Error())
+			return nil, nil, err
+		}
+		if !inserted {
+			continue
+		}
+		count++
+		if limit := s.limits.of(chat); limit > 0 && count > limit {
+			return nil, nil, ErrChatFull
+		}
+		added = append(added, userId)
+	}
+	return chat, added, nil
+}
+
+func (s *ChatManagementService) publishMembersAdded(chatId uuid.UUID, userIds []uuid.UUID) {
+	if len(userIds) > 0 {
+		s.publishEvent(dto.EventMembersAdded, chatId, userIds, nil)
+	}
+}
+
+// Lifts a group to the supergroup member limit. There is no way back, a
+// supergroup may already hold more members than a group can.
+func (s *ChatManagementService) UpgradeToSupergroup(actorId uuid.UUID, chatId uuid.UUID) (*models.Chat, error) {
+	chat, _, err := s.authorizeGroupAction(chatId, actorId, ActionManageChat)
+	if err != nil {
+		return nil, err
+	}
+	if chat.IsChannel {
+		return nil, ErrChannelUpgrade
+	}
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		chat, err = tx.LockChat(chatId)
+		if err != nil {
+			return err
+		}
+		if chat.IsSupergroup {
+			return ErrAlreadySupergroup
+		}
+		chat.IsSupergroup = true
+		return tx.UpdateChat(chat)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(dto.EventChatUpdated, chat.Id, nil, chat)
+	return chat, nil
+}
+
+func (s *ChatManagementService) GetMembers(chatId uuid.UUID, viewerId uuid.UUID, query *dto.MembersQuery) (*dto.MembersPageResponse, error) {
+	chat, _, err := s.authorizeIn(chatId, viewerId, ActionViewChat)
+	if err != nil {
+		return nil, err
+	}
+	return s.membersPage(chat, query)
+}
+
+// Names live in user_mgmt, so match is handed the member ids batch by batch.
+// Membership of the viewer is checked before any id leaves the service.
+func (s *ChatManagementService) SearchMembers(chatId uuid.UUID, viewerId uuid.UUID, match func(userIds []uuid.UUID, limit int) ([]uuid.UUID, error)) ([]uuid.UUID, error) {
+	_, _, err := s.authorizeIn(chatId, viewerId, ActionViewChat)
+	if err != nil {
+		return nil, err
+	}
+	matches := []uuid.UUID{}
+	var afterId uint
+	for len(matches) < MaxMemberMatches {
+		userChats, err := s.repo.GetChatMembersPage(chatId, afterId, "", nil, MemberSearchBatch)
+		if err != nil {
+			slog.Error("Failed to get chat members", "error", err.Error())
+			return nil, err
+		}
+		if len(userChats) == 0 {
+			break
+		}
+		userIds := make([]uuid.UUID, 0, len(userChats))
+		for _, userChat := range userChats {
+			userIds = append(userIds, userChat.UserId)
+		}
+		found, err := match(userIds, MaxMemberMatches-len(matches))
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, found...)
+		if len(userChats) < MemberSearchBatch {
+			break
+		}
+		afterId = userChats[len(userChats)-1].ID
+	}
+	return matches, nil
+}
+
+// For trusted services, lists members without a viewer.
+func (s *ChatManagementService) ListMembers(chatId uuid.UUID, query *dto.MembersQuery) (*dto.MembersPageResponse, error) {
+	chat, err := s.repo.FindById(chatId)
+	if err != nil {
+		return nil, err
+	}
+	return s.membersPage(chat, query)
+}
+
+func (s *ChatManagementService) membersPage(chat *models.Chat, query *dto.MembersQuery) (*dto.MembersPageResponse, error) {
+	role, userIds := query.Role, query.UserIds
+	switch role {
+	case "", models.MemberRoleAdmin, models.MemberRoleMember, models.MemberRoleReader:
+	case models.MemberRoleOwner:
+		role = ""
+		if chat.IsDirect {
+			userIds = []uuid.UUID{}
+		} else if userIds == nil || slices.Contains(userIds, chat.CreatorId) {
+			userIds = []uuid.UUID{chat.CreatorId}
+		} else {
+			userIds = []uuid.UUID{}
+		}
+	default:
+		return nil, ErrInvalidMemberRole
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultMembersLimit
+	}
+	if limit > MaxMembersLimit {
+		limit = MaxMembersLimit
+	}
+	var afterId uint
+	if query.Cursor != "" {
+		var err error
+		afterId, err = decodeMemberCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	page := &dto.MembersPageResponse{Members: []dto.ChatMember{}}
+	if userIds != nil && len(userIds) == 0 {
+		return page, nil
+	}
+	userChats, err := s.repo.GetChatMembersPage(chat.Id, afterId, role, userIds, limit+1)
+	if err != nil {
+		slog.Error("Failed to get chat members", "error", err.Error())
+		return nil, err
+	}
+	if len(userChats) > limit {
+		userChats = userChats[:limit]
+		page.NextCursor = encodeMemberCursor(userChats[limit-1].ID)
+	}
+	for _, userChat := range userChats {
+		member := dto.ChatMember{
+			UserId:    userChat.UserId,
+			Role:      memberRole(chat, &userChat),
+			JoinedAt:  userChat.CreatedAt,
+			InvitedBy: userChat.InvitedBy,
+		}
+		if userChat.IsAdmin {
+			member.AdminRights = userChat.AdminRights
+		}
+		page.Members = append(page.Members, member)
+	}
+	return page, nil
+}
+
+// Returns ErrNotMember for users outside the chat.
+func (s *ChatManagementService) GetMemberRole(chatId uuid.UUID, userId uuid.UUID) (string, error) {
+	chat, actor, err := s.findActor(chatId, userId)
+	if err != nil {
+		return "", err
+	}
+	if actor == nil {
+		return "", ErrNotMember
+	}
+	return memberRole(chat, actor), nil
+}
+
+func (s *ChatManagementService) GetMemberPermissions(chatId uuid.UUID, userId uuid.UUID) (*dto.MemberPermissions, error) {
+	chat, actor, err := s.findActor(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, ErrNotMember
+	}
+	permissions, rights := effectivePermissions(chat, actor)
+	return &dto.MemberPermissions{
+		Role:        memberRole(chat, actor),
+		Permissions: permissions,
+		AdminRights: rights,
+	}, nil
+}
+
+func (s *ChatManagementService) GetMembershipsForUser(userId uuid.UUID) ([]dto.ChatMembership, error) {
+	userChats, err := s.repo.GetChatsForUser(userId)
+	if err != nil {
+		slog.Error("Failed to get chats for user", "error", err.Error())
+		return nil, err
+	}
+	chatIds := make([]uuid.UUID, 0, len(userChats))
+	for _, userChat := range userChats {
+		chatIds = append(chatIds, userChat.ChatId)
+	}
+	chatsById, err := s.repo.FindByIds(chatIds)
+	if err != nil {
+		slog.Error("Failed to find chats", "error", err.Error())
+		return nil, err
+	}
+	memberships := make([]dto.ChatMembership, 0, len(userChats))
+	for i := range userChats {
+		chat, ok := chatsById[userChats[i].ChatId]
+		if !ok {
+			continue
+		}
+		memberships = append(memberships, dto.ChatMembership{Chat: chat, Role: memberRole(chat, &userChats[i])})
+	}
+	return memberships, nil
+}
+
+func memberRole(chat *models.Chat, userChat *models.UserChat) string {
+	switch {
+	case chat.IsOwner(userChat.UserId):
+		return models.MemberRoleOwner
+	case userChat.IsAdmin:
+		return models.MemberRoleAdmin
+	case userChat.ReadOnly:
+		return models.MemberRoleReader
+	}
+	return models.MemberRoleMember
+}
+
+func encodeMemberCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeMemberCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 0)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrNotMember       = errors.New("user is not a member of the chat")
+	ErrReadOnly        = errors.New("user is a reader in this chat")
+	ErrChannelPostDeny = errors.New("only admins can post in a channel")
+	ErrInvalidCursor   = errors.New("cursor is invalid")
+	ErrAttachTarget    = errors.New("files can only be attached to the sender's own text messages")
+)
+
+const (
+	DefaultMessagesLimit = 50
+	MaxMessagesLimit     = 100
+)
+
+func (s *ChatManagementService) getMembership(chatId uuid.UUID, userId uuid.UUID) (*models.UserChat, error) {
+	userChat, err := s.repo.FindUserChat(chatId, userId)
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, ErrNotMember
+	}
+	return userChat, err
+}
+
+func (s *ChatManagementService) SendMessage(chatId uuid.UUID, senderId uuid.UUID, req *dto.SendMessageRequest) (*models.Message, error) {
+	chat, userChat, err := s.authorizeIn(chatId, senderId, ActionSendMessage)
+	if err != nil {
+		return nil, err
+	}
+	if req.HasAttachments {
+		err = authorize(chat, userChat, ActionSendMedia)
+		if err != nil {
+			return nil, err
+		}
+	}
+	message := &models.Message{
+		Id:       uuid.New(),
+		ChatId:   chatId,
+		SenderId: senderId,
+		Text:     req.Text,
+		Type:     models.MessageTypeText,
+	}
+	if req.ReplyToId != nil {
+		parent, err := s.findChatMessage(chatId, *req.ReplyToId)
+		if err != nil {
+			return nil, err
+		}
+		message.ReplyToId = &parent.Id
+		message.ReplyToSenderId = &parent.SenderId
+		message.ReplyToText = parent.Text
+	}
+	var root *models.Message
+	if req.ThreadRootId != nil {
+		root, err = s.findChatMessage(chatId, *req.ThreadRootId)
+		if err != nil {
+			return nil, err
+		}
+		if root.ThreadRootId != nil {
+			return nil, ErrNotThreadRoot
+		}
+		message.ThreadRootId = &root.Id
+	}
+	err = s.repo.SaveMessage(message)
+	if err != nil {
+		slog.Error("Failed to save message", "error", err.Error())
+		return nil, err
+	}
+	s.indexMessage(message.Id)
+	if root != nil {
+		s.autoFollowThread(root, senderId)
+		s.autoFollowThread(root, root.SenderId)
+	}
+	s.publishEvent(dto.EventMessageCreated, chatId, nil, message)
+	return message, nil
+}
+
+func (s *ChatManagementService) GetMessages(chatId uuid.UUID, userId uuid.UUID, cursor string, limit int) (*dto.MessagesPageResponse, error) {
+	_, err := s.getMembership(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.getMessagesPage(chatId, userId, nil, cursor, limit)
+}
+
+func (s *ChatManagementService) getMessagesPage(chatId uuid.UUID, userId uuid.UUID, threadRootId *uuid.UUID, cursor string, limit int) (*dto.MessagesPageResponse, error) {
+	if limit <= 0 {
+		limit = DefaultMessagesLimit
+	}
+	if limit > MaxMessagesLimit {
+		limit = MaxMessagesLimit
+	}
+	var beforeCreatedAt *time.Time
+	var beforeId uuid.UUID
+	if cursor != "" {
+		createdAt, id, err := decodeMessageCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		beforeCreatedAt = &createdAt
+		beforeId = id
+	}
+	messages, err := s.repo.GetMessages(chatId, userId, threadRootId, beforeCreatedAt, beforeId, limit+1)
+	if err != nil {
+		slog.Error("Failed to get messages", "error", err.Error())
+		return nil, err
+	}
+	page := &dto.MessagesPageResponse{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		last := page.Messages[limit-1]
+		page.NextCursor = encodeMessageCursor(last.CreatedAt, last.Id)
+	}
+	err = s.fillReactions(page.Messages, userId)
+	if err != nil {
+		slog.Error("Failed to get reactions", "error", err.Error())
+		return nil, err
+	}
+	err = s.fillPolls(page.Messages, userId)
+	if err != nil {
+		slog.Error("Failed to get polls", "error", err.Error())
+		return nil, err
+	}
+	if threadRootId == nil {
+		rootIds := make([]uuid.UUID, 0, len(page.Messages))
+		for _, message := range page.Messages {
+			rootIds = append(rootIds, message.Id)
+		}
+		replies, err := s.repo.CountThreadReplies(rootIds)
+		if err != nil {
+			slog.Error("Failed to count thread replies", "error", err.Error())
+			return nil, err
+		}
+		for i := range page.Messages {
+			page.Messages[i].ThreadReplies = replies[page.Messages[i].Id]
+		}
+	}
+	return page, nil
+}
+
+// Listens for media_handler uploads tagged with a message id and attaches the
+// stored file to that message.
+func (s *ChatManagementService) RunFileLoadedListener(ctx context.Context) {
+	pubsub := s.repo.SubscribeFileLoadedChannel(ctx)
+	defer pubsub.Close()
+	for msg := range pubsub.Channel() {
+		var loaded dto.MessageIdXFileId
+		err := json.Unmarshal([]byte(msg.Payload), &loaded)
+		if err != nil {
+			slog.Error("Failed to decode file loaded event", "error", err.Error())
+			continue
+		}
+		err = s.attachFile(&loaded)
+		if err != nil {
+			slog.Error("Failed to attach file to message", "error", err.Error(), "messageId", loaded.MessageId, "userId", loaded.UserId)
+			continue
+		}
+		slog.Info(fmt.Sprintf("File %v attached to message %v", loaded.FileId, loaded.MessageId))
+	}
+}
+
+// Only the sender attaches files, to their own text messages, and only while
+// they may still send media in the chat.
+func (s *ChatManagementService) attachFile(loaded *dto.MessageIdXFileId) error {
+	message, err := s.repo.FindMessageById(loaded.MessageId)
+	if err != nil {
+		return err
+	}
+	if message.SenderId != loaded.UserId || message.Type != models.MessageTypeText {
+		return ErrAttachTarget
+	}
+	_, _, err = s.authorizeIn(message.ChatId, loaded.UserId, ActionSendMedia)
+	if err != nil {
+		return err
+	}
+	err = s.repo.AppendAttachment(message.Id, loaded.FileId)
+	if err != nil {
+		return err
+	}
+	// Clients already hold the message without the file, the edit event
+	// carries the updated attachments.
+	message, err = s.repo.FindMessageById(message.Id)
+	if err != nil {
+		return err
+	}
+	s.publishEvent(dto.EventMessageEdited, message.ChatId, nil, message)
+	return nil
+}
+
+func encodeMessageCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := fmt.Sprintf("%d_%s", createdAt.UnixNano(), id.String())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeMessageCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	nanos, rawId, found := strings.Cut(string(raw), "_")
+	if !found {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	return time.Unix(0, unixNano), id, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrNotSender         = errors.New("only the sender can edit the message")
+	ErrEditWindowExpired = errors.New("message can no longer be edited")
+	ErrDeleteDenied      = errors.New("not allowed to delete this message")
+	ErrNotChatAdmin      = errors.New("only chat admins can do this")
+	ErrServiceMessage    = errors.New("service messages cannot be edited")
+)
+
+func (s *ChatManagementService) findChatMessage(chatId uuid.UUID, messageId uuid.UUID) (*models.Message, error) {
+	message, err := s.repo.FindMessageById(messageId)
+	if err != nil {
+		return nil, err
+	}
+	if message.ChatId != chatId {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return message, nil
+}
+
+func (s *ChatManagementService) EditMessage(editorId uuid.UUID, req *dto.EditMessageRequest) (*models.Message, error) {
+	_, _, err := s.authorizeIn(req.ChatId, editorId, ActionSendMessage)
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.findChatMessage(req.ChatId, req.MessageId)
+	if err != nil {
+		return nil, err
+	}
+	if message.SenderId != editorId {
+		return nil, ErrNotSender
+	}
+	if message.Type == models.MessageTypeService {
+		return nil, ErrServiceMessage
+	}
+	if message.Type == models.MessageTypePoll {
+		return nil, ErrPollEdit
+	}
+	now := time.Now()
+	if now.Sub(message.CreatedAt) > s.messageEditWindow {
+		return nil, ErrEditWindowExpired
+	}
+	edit := &models.MessageEdit{
+		Id:        uuid.New(),
+		MessageId: message.Id,
+		EditorId:  editorId,
+		Text:      message.Text,
+		EditedAt:  now,
+	}
+	message.Text = req.Text
+	message.EditedAt = &now
+	err = s.repo.UpdateMessageText(message, edit)
+	if err != nil {
+		slog.Error("Failed to edit message", "error", err.Error())
+		return nil, err
+	}
+	s.indexMessage(message.Id)
+	s.publishEvent(dto.EventMessageEdited, message.ChatId, nil, message)
+	return message, nil
+}
+
+// Returns the previous versions of a message, oldest first.
+func (s *ChatManagementService) GetMessageEdits(chatId uuid.UUID, messageId uuid.UUID, userId uuid.UUID) ([]models.MessageEdit, error) {
+	_, _, err := s.authorizeIn(chatId, userId, ActionManageChat)
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.findChatMessage(chatId, messageId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetMessageEdits(message.Id)
+}
+
+func (s *ChatManagementService) DeleteMessage(userId uuid.UUID, req *dto.DeleteMessageRequest) error {
+	chat, err := s.repo.FindById(req.ChatId)
+	if err != nil {
+		return err
+	}
+	userChat, err := s.getMembership(req.ChatId, userId)
+	if err != nil {
+		return err
+	}
+	message, err := s.findChatMessage(req.ChatId, req.MessageId)
+	if err != nil {
+		return err
+	}
+	payload := &dto.MessageDeletedPayload{MessageId: message.Id}
+	if !req.ForEveryone {
+		err = s.repo.HideMessage(&models.MessageDeletion{MessageId: message.Id, UserId: userId})
+		if err != nil {
+			slog.Error("Failed to hide message", "error", err.Error())
+			return err
+		}
+		s.publishEvent(dto.EventMessageHidden, chat.Id, []uuid.UUID{userId}, payload)
+		return nil
+	}
+	if !s.canDeleteForEveryone(chat, userChat, message) {
+		return ErrDeleteDenied
+	}
+	err = s.repo.DeleteMessage(message.Id)
+	if err != nil {
+		slog.Error("Failed to delete message", "error", err.Error())
+		return err
+	}
+	s.publishEvent(dto.EventMessageDeleted, chat.Id, nil, payload)
+	s.dropPin(chat.Id, message.Id)
+	return nil
+}
+
+// The sender can always delete their message, anyone else needs the delete
+// right and to outrank the sender.
+func (s *ChatManagementService) canDeleteForEveryone(chat *models.Chat, actor *models.UserChat, message *models.Message) bool {
+	if message.SenderId == actor.UserId {
+		return true
+	}
+	sender, err := s.repo.FindUserChat(chat.Id, message.SenderId)
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return false
+	}
+	if err != nil {
+		sender = nil
+	}
+	return authorizeOn(chat, actor, sender, ActionDeleteMessages) == nil
+}
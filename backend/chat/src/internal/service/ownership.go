@@ -0,0 +1,232 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrTransferToSelf    = errors.New("chat already belongs to this user")
+	ErrNoPendingTransfer = errors.New("no pending ownership transfer")
+)
+
+const ownershipTransferTTL = 10 * time.Minute
+
+// Starts a transfer that the new owner has to accept before it expires.
+func (s *ChatManagementService) RequestOwnershipTransfer(ownerId uuid.UUID, req *dto.TransferOwnershipRequest) (*dto.OwnershipTransfer, error) {
+	chat, _, err := s.authorizeGroupAction(req.ChatId, ownerId, ActionTransferOwnership)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserId == ownerId {
+		return nil, ErrTransferToSelf
+	}
+	_, err = s.getMembership(chat.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	transfer := &dto.OwnershipTransfer{
+		ChatId:     chat.Id,
+		FromUserId: ownerId,
+		ToUserId:   req.UserId,
+		ExpiresAt:  time.Now().Add(ownershipTransferTTL),
+	}
+	raw, err := json.Marshal(transfer)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.SaveOwnershipTransfer(chat.Id, raw, ownershipTransferTTL)
+	if err != nil {
+		slog.Error("Failed to save ownership transfer", "error", err.Error())
+		return nil, err
+	}
+	s.publishEvent(dto.EventOwnerOffered, chat.Id, []uuid.UUID{req.UserId}, transfer)
+	return transfer, nil
+}
+
+// Accepted by the new owner. The transfer is dropped if the chat changed hands
+// in the meantime.
+func (s *ChatManagementService) ConfirmOwnershipTransfer(userId uuid.UUID, req *dto.ConfirmOwnershipRequest) error {
+	chat, _, err := s.authorizeIn(req.ChatId, userId, ActionViewChat)
+	if err != nil {
+		return err
+	}
+	if chat.IsDirect {
+		return ErrDirectChat
+	}
+	transfer, err := s.findOwnershipTransfer(chat.Id)
+	if err != nil {
+		return err
+	}
+	if transfer.ToUserId != userId {
+		return ErrNoPendingTransfer
+	}
+	ownerId := transfer.FromUserId
+	var successor *models.UserChat
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		chat, err = tx.LockChat(chat.Id)
+		if err != nil {
+			return err
+		}
+		if chat.CreatorId != ownerId {
+			return ErrNoPendingTransfer
+		}
+		successor, err = tx.FindUserChat(chat.Id, userId)
+		if gorm.IsRecordNotFoundError(err) {
+			return ErrNotMember
+		}
+		if err != nil {
+			return err
+		}
+		return transferOwnershipTx(tx, chat, successor)
+	})
+	if err != nil {
+		return err
+	}
+	s.ownerChanged(chat.Id, ownerId, successor.UserId)
+	return nil
+}
+
+func (s *ChatManagementService) CancelOwnershipTransfer(ownerId uuid.UUID, chatId uuid.UUID) error {
+	_, _, err := s.authorizeIn(chatId, ownerId, ActionTransferOwnership)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteOwnershipTransfer(chatId)
+}
+
+func (s *ChatManagementService) LeaveChat(chatId uuid.UUID, userId uuid.UUID) error {
+	chat, err := s.repo.FindById(chatId)
+	if err != nil {
+		return err
+	}
+	if chat.IsDirect {
+		return ErrDirectChat
+	}
+	return s.leaveChat(chat, userId)
+}
+
+// Used when an account is deleted. Direct chats are left too so the peer
+// keeps the history.
+func (s *ChatManagementService) LeaveAllChats(userId uuid.UUID) ([]uuid.UUID, error) {
+	userChats, err := s.repo.GetChatsForUser(userId)
+	if err != nil {
+		slog.Error("Failed to get chats for user", "error", err.Error())
+		return nil, err
+	}
+	chatIds := make([]uuid.UUID, 0, len(userChats))
+	for _, userChat := range userChats {
+		chat, err := s.repo.FindById(userChat.ChatId)
+		if err != nil {
+			slog.Error("Failed to find chat", "error", err.Error())
+			return chatIds, err
+		}
+		err = s.leaveChat(chat, userId)
+		if err != nil {
+			return chatIds, err
+		}
+		chatIds = append(chatIds, chat.Id)
+	}
+	return chatIds, nil
+}
+
+// An owner who leaves hands the chat to the longest-serving admin, or else
+// the longest-serving member. The last one out deletes the chat.
+func (s *ChatManagementService) leaveChat(chat *models.Chat, userId uuid.UUID) error {
+	var successor *models.UserChat
+	lastMember := false
+	err := s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		chat, err := tx.LockChat(chat.Id)
+		if err != nil {
+			return err
+		}
+		userChat, err := tx.FindUserChat(chat.Id, userId)
+		if gorm.IsRecordNotFoundError(err) {
+			return ErrNotMember
+		}
+		if err != nil {
+			return err
+		}
+		if chat.IsOwner(userId) {
+			successor, err = tx.FindSuccessor(chat.Id, userId)
+			if gorm.IsRecordNotFoundError(err) {
+				lastMember = true
+				return nil
+			}
+			if err != nil {
+				slog.Error("Failed to find chat successor", "error", err.Error())
+				return err
+			}
+			err = transferOwnershipTx(tx, chat, successor)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.RemoveUserFromChat(userChat)
+	})
+	if err != nil {
+		return err
+	}
+	if lastMember {
+		return s.DeleteChat(chat.Id)
+	}
+	if successor != nil {
+		s.ownerChanged(chat.Id, userId, successor.UserId)
+	}
+	s.publishEvent(dto.EventMembersRemoved, chat.Id, []uuid.UUID{userId}, nil)
+	return nil
+}
+
+func transferOwnershipTx(tx *repository.ChatRepository, chat *models.Chat, successor *models.UserChat) error {
+	chat.CreatorId = successor.UserId
+	err := tx.UpdateChat(chat)
+	if err != nil {
+		slog.Error("Failed to update chat", "error", err.Error())
+		return err
+	}
+	successor.IsAdmin = true
+	successor.ReadOnly = false
+	successor.AdminRights = models.AllAdminRights
+	err = tx.UpdateUserChat(successor)
+	if err != nil {
+		slog.Error("Failed to update user chat", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (s *ChatManagementService) ownerChanged(chatId uuid.UUID, previousOwnerId uuid.UUID, ownerId uuid.UUID) {
+	err := s.repo.DeleteOwnershipTransfer(chatId)
+	if err != nil {
+		slog.Error("Failed to clear ownership transfer", "error", err.Error())
+	}
+	s.publishEvent(dto.EventOwnerChanged, chatId, []uuid.UUID{previousOwnerId, ownerId}, &dto.OwnerChangedPayload{
+		PreviousOwnerId: previousOwnerId,
+		OwnerId:         ownerId,
+	})
+}
+
+func (s *ChatManagementService) findOwnershipTransfer(chatId uuid.UUID) (*dto.OwnershipTransfer, error) {
+	raw, err := s.repo.FindOwnershipTransfer(chatId)
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNoPendingTransfer
+	}
+	if err != nil {
+		return nil, err
+	}
+	var transfer dto.OwnershipTransfer
+	err = json.Unmarshal(raw, &transfer)
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+func (s *ChatManagementService) PinMessage(actorId uuid.UUID, req *dto.PinMessageRequest) (*dto.Pin, error) {
+	chat, _, err := s.authorizeIn(req.ChatId, actorId, ActionPinMessages)
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.findChatMessage(chat.Id, req.MessageId)
+	if err != nil {
+		return nil, err
+	}
+	pin := &models.PinnedMessage{
+		ChatId:    chat.Id,
+		MessageId: message.Id,
+		PinnedBy:  actorId,
+		PinnedAt:  time.Now(),
+	}
+	err = s.repo.SavePin(pin)
+	if err != nil {
+		slog.Error("Failed to pin message", "error", err.Error())
+		return nil, err
+	}
+	result := &dto.Pin{Message: message, PinnedBy: pin.PinnedBy, PinnedAt: pin.PinnedAt}
+	s.publishEvent(dto.EventPinned, chat.Id, nil, result)
+	s.postServiceMessage(chat.Id, actorId, &models.ServiceAction{Type: models.ServiceMessagePinned, MessageId: &message.Id})
+	return result, nil
+}
+
+func (s *ChatManagementService) UnpinMessage(actorId uuid.UUID, req *dto.PinMessageRequest) error {
+	chat, _, err := s.authorizeIn(req.ChatId, actorId, ActionPinMessages)
+	if err != nil {
+		return err
+	}
+	removed, err := s.repo.DeletePin(chat.Id, req.MessageId)
+	if err != nil {
+		slog.Error("Failed to unpin message", "error", err.Error())
+		return err
+	}
+	if !removed {
+		return gorm.ErrRecordNotFound
+	}
+	s.publishEvent(dto.EventUnpinned, chat.Id, nil, &dto.MessagesUnpinnedPayload{MessageIds: []uuid.UUID{req.MessageId}})
+	return nil
+}
+
+func (s *ChatManagementService) UnpinAllMessages(actorId uuid.UUID, chatId uuid.UUID) error {
+	chat, _, err := s.authorizeIn(chatId, actorId, ActionPinMessages)
+	if err != nil {
+		return err
+	}
+	pins, err := s.repo.GetPins(chat.Id, 0)
+	if err != nil {
+		slog.Error("Failed to get pins", "error", err.Error())
+		return err
+	}
+	if len(pins) == 0 {
+		return nil
+	}
+	err = s.repo.DeletePins(chat.Id)
+	if err != nil {
+		slog.Error("Failed to unpin messages", "error", err.Error())
+		return err
+	}
+	payload := &dto.MessagesUnpinnedPayload{}
+	for _, pin := range pins {
+		payload.MessageIds = append(payload.MessageIds, pin.MessageId)
+	}
+	s.publishEvent(dto.EventUnpinned, chat.Id, nil, payload)
+	return nil
+}
+
+// Latest pin first.
+func (s *ChatManagementService) GetPins(chatId uuid.UUID, userId uuid.UUID) ([]dto.Pin, error) {
+	_, _, err := s.authorizeIn(chatId, userId, ActionViewChat)
+	if err != nil {
+		return nil, err
+	}
+	return s.pins(chatId, 0)
+}
+
+func (s *ChatManagementService) pins(chatId uuid.UUID, limit int) ([]dto.Pin, error) {
+	pinned, err := s.repo.GetPins(chatId, limit)
+	if err != nil {
+		slog.Error("Failed to get pins", "error", err.Error())
+		return nil, err
+	}
+	result := make([]dto.Pin, 0, len(pinned))
+	if len(pinned) == 0 {
+		return result, nil
+	}
+	messageIds := make([]uuid.UUID, 0, len(pinned))
+	for _, pin := range pinned {
+		messageIds = append(messageIds, pin.MessageId)
+	}
+	messages, err := s.repo.GetMessagesByIds(messageIds)
+	if err != nil {
+		slog.Error("Failed to get pinned messages", "error", err.Error())
+		return nil, err
+	}
+	byId := make(map[uuid.UUID]*models.Message, len(messages))
+	for i := range messages {
+		byId[messages[i].Id] = &messages[i]
+	}
+	for _, pin := range pinned {
+		message, ok := byId[pin.MessageId]
+		if !ok {
+			continue
+		}
+		result = append(result, dto.Pin{Message: message, PinnedBy: pin.PinnedBy, PinnedAt: pin.PinnedAt})
+	}
+	return result, nil
+}
+
+func (s *ChatManagementService) dropPin(chatId uuid.UUID, messageId uuid.UUID) {
+	removed, err := s.repo.DeletePin(chatId, messageId)
+	if err != nil {
+		slog.Error("Failed to unpin deleted message", "error", err.Error())
+		return
+	}
+	if removed {
+		s.publishEvent(dto.EventUnpinned, chatId, nil, &dto.MessagesUnpinnedPayload{MessageIds: []uuid.UUID{messageId}})
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrPermissionDenied  = errors.New("chat permissions do not allow this")
+	ErrMissingAdminRight = errors.New("admin rights do not allow this")
+	ErrNotChatCreator    = errors.New("only the chat creator can do this")
+	ErrOutranked         = errors.New("cannot act on a member of equal or higher rank")
+)
+
+type Action int
+
+const (
+	ActionViewChat Action = iota
+	ActionSendMessage
+	ActionSendMedia
+	ActionReact
+	ActionVote
+	ActionEmitSignal
+	ActionAddMembers
+	ActionPinMessages
+	ActionChangeInfo
+	// Invite links, join requests and edit history, open to every admin.
+	ActionManageChat
+	ActionBanUsers
+	ActionDeleteMessages
+	ActionPromoteMembers
+	ActionDeleteChat
+	ActionTransferOwnership
+)
+
+var memberActions = map[Action]models.Permission{
+	ActionSendMessage: models.PermSendMessages,
+	ActionSendMedia:   models.PermSendMedia,
+	ActionAddMembers:  models.PermAddMembers,
+	ActionPinMessages: models.PermPinMessages,
+	ActionChangeInfo:  models.PermChangeInfo,
+}
+
+var adminActions = map[Action]models.AdminRight{
+	ActionBanUsers:       models.RightBanUsers,
+	ActionDeleteMessages: models.RightDeleteMessages,
+	ActionPromoteMembers: models.RightPromoteMembers,
+}
+
+// The single place chat permissions are decided. A nil actor is not a member
+// of the chat. The creator holds every permission and right, admins hold
+// every member permission and their own rights, and members are limited by
+// the chat's member permissions unless they are readers.
+func authorize(chat *models.Chat, actor *models.UserChat, action Action) error {
+	if actor == nil {
+		return ErrNotMember
+	}
+	isCreator := chat.IsOwner(actor.UserId)
+	isAdmin := isCreator || actor.IsAdmin
+	switch action {
+	case ActionViewChat:
+		return nil
+	case ActionReact:
+		if actor.ReadOnly && !isAdmin && !chat.ReadersCanReact {
+			return ErrReadersCannotReact
+		}
+		return nil
+	case ActionVote:
+		if actor.ReadOnly && !isAdmin && !chat.ReadersCanVote {
+			return ErrReadersCannotVote
+		}
+		return nil
+	case ActionEmitSignal:
+		if actor.ReadOnly && !isAdmin {
+			return ErrReadersCannotEmit
+		}
+		return nil
+	case ActionManageChat:
+		if !isAdmin {
+			return ErrNotChatAdmin
+		}
+		return nil
+	case ActionDeleteChat, ActionTransferOwnership:
+		if !isCreator {
+			return ErrNotChatCreator
+		}
+		return nil
+	}
+	if permission, ok := memberActions[action]; ok {
+		if isAdmin {
+			return nil
+		}
+		if chat.IsChannel && (action == ActionSendMessage || action == ActionSendMedia) {
+			return ErrChannelPostDeny
+		}
+		if actor.ReadOnly {
+			return ErrReadOnly
+		}
+		if chat.MemberPermissions&permission == 0 {
+			return ErrPermissionDenied
+		}
+		return nil
+	}
+	if right, ok := adminActions[action]; ok {
+		if isCreator {
+			return nil
+		}
+		if !actor.IsAdmin {
+			return ErrNotChatAdmin
+		}
+		if actor.AdminRights&right == 0 {
+			return ErrMissingAdminRight
+		}
+		return nil
+	}
+	return ErrPermissionDenied
+}
+
+// Like authorize, but for actions aimed at another member. Nobody can act on
+// the creator and only the creator can act on admins. A nil target is no
+// longer a member and outranks nobody.
+func authorizeOn(chat *models.Chat, actor *models.UserChat, target *models.UserChat, action Action) error {
+	err := authorize(chat, actor, action)
+	if err != nil {
+		return err
+	}
+	if target == nil || target.UserId == actor.UserId {
+		return nil
+	}
+	if chat.IsOwner(target.UserId) {
+		return ErrOutranked
+	}
+	if target.IsAdmin && !chat.IsOwner(actor.UserId) {
+		return ErrOutranked
+	}
+	return nil
+}
+
+// Rights an actor can hand out when promoting, nobody grants more than they
+// hold.
+func grantableRights(chat *models.Chat, actor *models.UserChat) models.AdminRight {
+	if chat.IsOwner(actor.UserId) {
+		return models.AllAdminRights
+	}
+	return actor.AdminRights
+}
+
+// What authorize allows the actor, as bit sets.
+func effectivePermissions(chat *models.Chat, actor *models.UserChat) (models.Permission, models.AdminRight) {
+	var permissions models.Permission
+	for action, permission := range memberActions {
+		if authorize(chat, actor, action) == nil {
+			permissions |= permission
+		}
+	}
+	var rights models.AdminRight
+	for action, right := range adminActions {
+		if authorize(chat, actor, action) == nil {
+			rights |= right
+		}
+	}
+	return permissions, rights
+}
+
+func (s *ChatManagementService) findActor(chatId uuid.UUID, userId uuid.UUID) (*models.Chat, *models.UserChat, error) {
+	chat, err := s.repo.FindById(chatId)
+	if err != nil {
+		return nil, nil, err
+	}
+	userChat, err := s.repo.FindUserChat(chatId, userId)
+	if gorm.IsRecordNotFoundError(err) {
+		return chat, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return chat, userChat, nil
+}
+
+func (s *ChatManagementService) authorizeIn(chatId uuid.UUID, userId uuid.UUID, action Action) (*models.Chat, *models.UserChat, error) {
+	chat, actor, err := s.findActor(chatId, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = authorize(chat, actor, action)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chat, actor, nil
+}
+
+func (s *ChatManagementService) Authorize(chatId uuid.UUID, userId uuid.UUID, action Action) error {
+	_, _, err := s.authorizeIn(chatId, userId, action)
+	return err
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestAuthorize(t *testing.T) {
+	creatorId := uuid.New()
+	group := &models.Chat{CreatorId: creatorId, MemberPermissions: models.DefaultMemberPermissions}
+	openGroup := &models.Chat{CreatorId: creatorId, MemberPermissions: models.AllPermissions}
+	lockedGroup := &models.Chat{CreatorId: creatorId, MemberPermissions: 0}
+	reactingGroup := &models.Chat{CreatorId: creatorId, MemberPermissions: models.DefaultMemberPermissions, ReadersCanReact: true}
+	channel := &models.Chat{CreatorId: creatorId, IsChannel: true, MemberPermissions: models.DefaultMemberPermissions}
+	votingChannel := &models.Chat{CreatorId: creatorId, IsChannel: true, MemberPermissions: models.DefaultMemberPermissions, ReadersCanVote: true}
+	direct := &models.Chat{CreatorId: creatorId, IsDirect: true, MemberPermissions: models.DefaultMemberPermissions}
+
+	creator := &models.UserChat{UserId: creatorId, IsAdmin: true, AdminRights: models.AllAdminRights}
+	fullAdmin := &models.UserChat{UserId: uuid.New(), IsAdmin: true, AdminRights: models.AllAdminRights}
+	banAdmin := &models.UserChat{UserId: uuid.New(), IsAdmin: true, AdminRights: models.RightBanUsers}
+	bareAdmin := &models.UserChat{UserId: uuid.New(), IsAdmin: true}
+	member := &models.UserChat{UserId: uuid.New()}
+	reader := &models.UserChat{UserId: uuid.New(), ReadOnly: true}
+
+	tests := []struct {
+		name   string
+		chat   *models.Chat
+		actor  *models.UserChat
+		action Action
+		want   error
+	}{
+		{"non-member cannot view", group, nil, ActionViewChat, ErrNotMember},
+		{"non-member cannot send", group, nil, ActionSendMessage, ErrNotMember},
+		{"reader can view", group, reader, ActionViewChat, nil},
+
+		{"member sends by default", group, member, ActionSendMessage, nil},
+		{"member sends media by default", group, member, ActionSendMedia, nil},
+		{"member cannot add members by default", group, member, ActionAddMembers, ErrPermissionDenied},
+		{"member cannot pin by default", group, member, ActionPinMessages, ErrPermissionDenied},
+		{"member cannot change info by default", group, member, ActionChangeInfo, ErrPermissionDenied},
+		{"member adds members when allowed", openGroup, member, ActionAddMembers, nil},
+		{"member pins when allowed", openGroup, member, ActionPinMessages, nil},
+		{"member changes info when allowed", openGroup, member, ActionChangeInfo, nil},
+		{"member cannot send in locked chat", lockedGroup, member, ActionSendMessage, ErrPermissionDenied},
+		{"admin sends in locked chat", lockedGroup, bareAdmin, ActionSendMessage, nil},
+		{"admin changes info without member permission", group, bareAdmin, ActionChangeInfo, nil},
+
+		{"reader cannot send", openGroup, reader, ActionSendMessage, ErrReadOnly},
+		{"reader cannot add members", openGroup, reader, ActionAddMembers, ErrReadOnly},
+		{"reader cannot react by default", group, reader, ActionReact, ErrReadersCannotReact},
+		{"reader reacts when allowed", reactingGroup, reader, ActionReact, nil},
+		{"reader cannot signal", group, reader, ActionEmitSignal, ErrReadersCannotEmit},
+		{"member reacts", group, member, ActionReact, nil},
+		{"member votes", group, member, ActionVote, nil},
+		{"reader cannot vote by default", channel, reader, ActionVote, ErrReadersCannotVote},
+		{"reader votes when allowed", votingChannel, reader, ActionVote, nil},
+		{"admin votes as reader", channel, &models.UserChat{UserId: uuid.New(), IsAdmin: true, ReadOnly: true}, ActionVote, nil},
+		{"non-member cannot vote", votingChannel, nil, ActionVote, ErrNotMember},
+		{"member signals", group, member, ActionEmitSignal, nil},
+
+		{"channel member cannot post", channel, member, ActionSendMessage, ErrChannelPostDeny},
+		{"channel member cannot post media", channel, member, ActionSendMedia, ErrChannelPostDeny},
+		{"channel admin posts", channel, bareAdmin, ActionSendMessage, nil},
+
+		{"member cannot manage chat", group, member, ActionManageChat, ErrNotChatAdmin},
+		{"admin without rights manages chat", group, bareAdmin, ActionManageChat, nil},
+
+		{"member cannot ban", openGroup, member, ActionBanUsers, ErrNotChatAdmin},
+		{"admin without ban right cannot ban", group, bareAdmin, ActionBanUsers, ErrMissingAdminRight},
+		{"admin with ban right bans", group, banAdmin, ActionBanUsers, nil},
+		{"ban right does not delete messages", group, banAdmin, ActionDeleteMessages, ErrMissingAdminRight},
+		{"ban right does not promote", group, banAdmin, ActionPromoteMembers, ErrMissingAdminRight},
+		{"full admin deletes messages", group, fullAdmin, ActionDeleteMessages, nil},
+		{"full admin promotes", group, fullAdmin, ActionPromoteMembers, nil},
+		{"creator holds every right", group, creator, ActionPromoteMembers, nil},
+		{"creator without stored rights still bans", group, &models.UserChat{UserId: creatorId}, ActionBanUsers, nil},
+
+		{"admin cannot delete chat", group, fullAdmin, ActionDeleteChat, ErrNotChatCreator},
+		{"creator deletes chat", group, creator, ActionDeleteChat, nil},
+		{"admin cannot transfer ownership", group, fullAdmin, ActionTransferOwnership, ErrNotChatCreator},
+		{"creator transfers ownership", group, creator, ActionTransferOwnership, nil},
+
+		{"direct chat opener cannot delete chat", direct, &models.UserChat{UserId: creatorId}, ActionDeleteChat, ErrNotChatCreator},
+		{"direct chat opener cannot transfer ownership", direct, &models.UserChat{UserId: creatorId}, ActionTransferOwnership, ErrNotChatCreator},
+		{"direct chat opener cannot ban", direct, &models.UserChat{UserId: creatorId}, ActionBanUsers, ErrNotChatAdmin},
+		{"direct chat peer sends", direct, member, ActionSendMessage, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorize(tt.chat, tt.actor, tt.action)
+
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeOn(t *testing.T) {
+	creatorId := uuid.New()
+	chat := &models.Chat{CreatorId: creatorId, MemberPermissions: models.DefaultMemberPermissions}
+
+	creator := &models.UserChat{UserId: creatorId, IsAdmin: true, AdminRights: models.AllAdminRights}
+	admin := &models.UserChat{UserId: uuid.New(), IsAdmin: true, AdminRights: models.AllAdminRights}
+	otherAdmin := &models.UserChat{UserId: uuid.New(), IsAdmin: true, AdminRights: models.AllAdminRights}
+	member := &models.UserChat{UserId: uuid.New()}
+	otherMember := &models.UserChat{UserId: uuid.New()}
+
+	tests := []struct {
+		name   string
+		actor  *models.UserChat
+		target *models.UserChat
+		action Action
+		want   error
+	}{
+		{"admin bans member", admin, member, ActionBanUsers, nil},
+		{"admin cannot ban admin", admin, otherAdmin, ActionBanUsers, ErrOutranked},
+		{"admin cannot ban creator", admin, creator, ActionBanUsers, ErrOutranked},
+		{"creator bans admin", creator, admin, ActionBanUsers, nil},
+		{"admin deletes member's messages", admin, member, ActionDeleteMessages, nil},
+		{"admin cannot delete admin's messages", admin, otherAdmin, ActionDeleteMessages, ErrOutranked},
+		{"admin cannot delete creator's messages", admin, creator, ActionDeleteMessages, ErrOutranked},
+		{"admin acts on departed member", admin, nil, ActionDeleteMessages, nil},
+		{"admin demotes self", admin, admin, ActionPromoteMembers, nil},
+		{"admin cannot demote admin", admin, otherAdmin, ActionPromoteMembers, ErrOutranked},
+		{"creator demotes admin", creator, admin, ActionPromoteMembers, nil},
+		{"member cannot ban member", member, otherMember, ActionBanUsers, ErrNotChatAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeOn(chat, tt.actor, tt.target, tt.action)
+
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestGrantableRights(t *testing.T) {
+	creatorId := uuid.New()
+	chat := &models.Chat{CreatorId: creatorId}
+
+	tests := []struct {
+		name  string
+		actor *models.UserChat
+		want  models.AdminRight
+	}{
+		{"creator grants every right", &models.UserChat{UserId: creatorId}, models.AllAdminRights},
+		{"admin grants own rights", &models.UserChat{UserId: uuid.New(), IsAdmin: true, AdminRights: models.RightPromoteMembers | models.RightBanUsers}, models.RightPromoteMembers | models.RightBanUsers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := grantableRights(chat, tt.actor)
+
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEffectivePermissions(t *testing.T) {
+	creatorId := uuid.New()
+	group := &models.Chat{CreatorId: creatorId, MemberPermissions: models.DefaultMemberPermissions}
+	channel := &models.Chat{CreatorId: creatorId, IsChannel: true, MemberPermissions: models.AllPermissions}
+
+	tests := []struct {
+		name            string
+		chat            *models.Chat
+		actor           *models.UserChat
+		wantPermissions models.Permission
+		wantRights      models.AdminRight
+	}{
+		{"creator", group, &models.UserChat{UserId: creatorId}, models.AllPermissions, models.AllAdminRights},
+		{"admin with ban right", group, &models.UserChat{UserId: uuid.New(), IsAdmin: true, AdminRights: models.RightBanUsers}, models.AllPermissions, models.RightBanUsers},
+		{"member", group, &models.UserChat{UserId: uuid.New()}, models.DefaultMemberPermissions, 0},
+		{"reader", group, &models.UserChat{UserId: uuid.New(), ReadOnly: true}, 0, 0},
+		{"channel member", channel, &models.UserChat{UserId: uuid.New()}, models.PermAddMembers | models.PermPinMessages | models.PermChangeInfo, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions, rights := effectivePermissions(tt.chat, tt.actor)
+
+			if permissions != tt.wantPermissions {
+				t.Fatalf("expected permissions %v, got %v", tt.wantPermissions, permissions)
+			}
+			if rights != tt.wantRights {
+				t.Fatalf("expected rights %v, got %v", tt.wantRights, rights)
+			}
+		})
+	}
+}
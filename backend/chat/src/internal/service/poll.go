@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrNotPoll           = errors.New("message is not a poll")
+	ErrPollEdit          = errors.New("polls cannot be edited")
+	ErrPollClosed        = errors.New("poll is closed")
+	ErrAlreadyVoted      = errors.New("already voted in this poll")
+	ErrInvalidPollOption = errors.New("poll option is invalid")
+	ErrSingleChoice      = errors.New("poll allows only one option")
+	ErrQuizRetract       = errors.New("quiz answers cannot be retracted")
+	ErrAnonymousPoll     = errors.New("votes in this poll are anonymous")
+	ErrReadersCannotVote = errors.New("readers cannot vote in this chat")
+	ErrPollCloseDenied   = errors.New("not allowed to close this poll")
+)
+
+const pollsBatch = 100
+
+func (s *ChatManagementService) SendPoll(senderId uuid.UUID, req *dto.SendPollRequest) (*models.Message, error) {
+	_, _, err := s.authorizeIn(req.ChatId, senderId, ActionSendMessage)
+	if err != nil {
+		return nil, err
+	}
+	message := &models.Message{
+		Id:       uuid.New(),
+		ChatId:   req.ChatId,
+		SenderId: senderId,
+		Text:     req.Question,
+		Type:     models.MessageTypePoll,
+	}
+	poll := &models.Poll{
+		MessageId:      message.Id,
+		ChatId:         req.ChatId,
+		Question:       req.Question,
+		Options:        req.Options,
+		Public:         req.Public,
+		MultipleChoice: req.MultipleChoice,
+		Quiz:           req.Quiz,
+		CorrectOption:  req.CorrectOption,
+		Explanation:    req.Explanation,
+		ClosesAt:       req.ClosesAt,
+	}
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		err := tx.SaveMessage(message)
+		if err != nil {
+			return err
+		}
+		return tx.SavePoll(poll)
+	})
+	if err != nil {
+		slog.Error("Failed to save poll", "error", err.Error())
+		return nil, err
+	}
+	s.indexMessage(message.Id)
+	polls, err := s.loadPolls([]uuid.UUID{message.Id}, senderId)
+	if err != nil {
+		slog.Error("Failed to load poll", "error", err.Error())
+		return nil, err
+	}
+	message.Poll = polls[message.Id]
+	s.publishEvent(dto.EventMessageCreated, message.ChatId, nil, message)
+	return message, nil
+}
+
+func (s *ChatManagementService) Vote(userId uuid.UUID, req *dto.PollVoteRequest) (*models.Poll, error) {
+	_, _, err := s.authorizeIn(req.ChatId, userId, ActionVote)
+	if err != nil {
+		return nil, err
+	}
+	var poll *models.Poll
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		var err error
+		poll, err = tx.LockPoll(req.ChatId, req.MessageId)
+		if err != nil {
+			return err
+		}
+		if poll.IsClosed(time.Now()) {
+			return ErrPollClosed
+		}
+		options, err := checkPollOptions(poll, req.Options)
+		if err != nil {
+			return err
+		}
+		votes, err := tx.GetPollVotes([]uuid.UUID{poll.MessageId}, &userId)
+		if err != nil {
+			return err
+		}
+		if len(votes) > 0 {
+			return ErrAlreadyVoted
+		}
+		return tx.InsertPollVotes(poll.MessageId, userId, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.pollChanged(poll.MessageId, userId)
+}
+
+func (s *ChatManagementService) RetractVote(userId uuid.UUID, req *dto.PollRequest) (*models.Poll, error) {
+	_, _, err := s.authorizeIn(req.ChatId, userId, ActionVote)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		poll, err := tx.LockPoll(req.ChatId, req.MessageId)
+		if err != nil {
+			return err
+		}
+		if poll.IsClosed(time.Now()) {
+			return ErrPollClosed
+		}
+		if poll.Quiz {
+			return ErrQuizRetract
+		}
+		removed, err := tx.DeletePollVotes(poll.MessageId, userId)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.pollChanged(req.MessageId, userId)
+}
+
+// The sender closes their poll, anyone else needs the delete right.
+func (s *ChatManagementService) ClosePoll(actorId uuid.UUID, req *dto.PollRequest) (*models.Poll, error) {
+	chat, actor, err := s.authorizeIn(req.ChatId, actorId, ActionViewChat)
+	if err != nil {
+		return nil, err
+	}
+	message, poll, err := s.findChatPoll(chat.Id, req.MessageId)
+	if err != nil {
+		return nil, err
+	}
+	if message.SenderId != actorId && authorize(chat, actor, ActionDeleteMessages) != nil {
+		return nil, ErrPollCloseDenied
+	}
+	if poll.IsClosed(time.Now()) {
+		return nil, ErrPollClosed
+	}
+	closed, err := s.repo.ClosePoll(poll.MessageId, time.Now())
+	if err != nil {
+		slog.Error("Failed to close poll", "error", err.Error())
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrPollClosed
+	}
+	return s.pollChanged(poll.MessageId, actorId)
+}
+
+func (s *ChatManagementService) GetPollVoters(chatId uuid.UUID, messageId uuid.UUID, userId uuid.UUID) ([]dto.PollVoter, error) {
+	_, _, err := s.authorizeIn(chatId, userId, ActionViewChat)
+	if err != nil {
+		return nil, err
+	}
+	_, poll, err := s.findChatPoll(chatId, messageId)
+	if err != nil {
+		return nil, err
+	}
+	if !poll.Public {
+		return nil, ErrAnonymousPoll
+	}
+	votes, err := s.repo.GetPollVotes([]uuid.UUID{poll.MessageId}, nil)
+	if err != nil {
+		slog.Error("Failed to get poll votes", "error", err.Error())
+		return nil, err
+	}
+	voters := make([]dto.PollVoter, 0)
+	byUser := make(map[uuid.UUID]int)
+	for _, vote := range votes {
+		i, ok := byUser[vote.UserId]
+		if !ok {
+			i = len(voters)
+			byUser[vote.UserId] = i
+			voters = append(voters, dto.PollVoter{UserId: vote.UserId})
+		}
+		voters[i].Options = append(voters[i].Options, vote.Option)
+	}
+	return voters, nil
+}
+
+// Closes polls whose time is up until ctx is cancelled.
+func (s *ChatManagementService) RunPollSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweepPolls()
+		}
+	}
+}
+
+func (s *ChatManagementService) sweepPolls() {
+	for {
+		polls, err := s.repo.GetExpiredPolls(time.Now(), pollsBatch)
+		if err != nil {
+			slog.Error("Failed to get expired polls", "error", err.Error())
+			return
+		}
+		// As with restrictions, paging stops after a failure and the next tick
+		// retries.
+		failed := false
+		for _, poll := range polls {
+			closed, err := s.repo.ClosePoll(poll.MessageId, *poll.ClosesAt)
+			if err != nil {
+				slog.Error("Failed to close poll", "error", err.Error(), "messageId", poll.MessageId)
+				failed = true
+				continue
+			}
+			if closed {
+				s.pollChanged(poll.MessageId, uuid.Nil)
+			}
+		}
+		if failed || len(polls) < pollsBatch {
+			return
+		}
+	}
+}
+
+func (s *ChatManagementService) findChatPoll(chatId uuid.UUID, messageId uuid.UUID) (*models.Message, *models.Poll, error) {
+	message, err := s.findChatMessage(chatId, messageId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if message.Type != models.MessageTypePoll {
+		return nil, nil, ErrNotPoll
+	}
+	polls, err := s.repo.GetPolls([]uuid.UUID{message.Id})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(polls) == 0 {
+		return nil, nil, ErrNotPoll
+	}
+	return message, &polls[0], nil
+}
+
+// Sorts and dedupes the picked options.
+func checkPollOptions(poll *models.Poll, options []int) ([]int, error) {
+	picked := slices.Clone(options)
+	slices.Sort(picked)
+	picked = slices.Compact(picked)
+	if len(picked) == 0 {
+		return nil, ErrInvalidPollOption
+	}
+	if picked[0] < 0 || picked[len(picked)-1] >= len(poll.Options) {
+		return nil, ErrInvalidPollOption
+	}
+	if len(picked) > 1 && !poll.MultipleChoice {
+		return nil, ErrSingleChoice
+	}
+	return picked, nil
+}
+
+// Quiz answers stay hidden until the viewer answered or the poll closed.
+func revealsAnswer(poll *models.Poll, now time.Time) bool {
+	return !poll.Quiz || len(poll.Voted) > 0 || poll.IsClosed(now)
+}
+
+// Broadcasts fresh tallies and returns the poll as the viewer sees it.
+func (s *ChatManagementService) pollChanged(messageId uuid.UUID, viewerId uuid.UUID) (*models.Poll, error) {
+	polls, err := s.loadPolls([]uuid.UUID{messageId}, viewerId)
+	if err != nil {
+		slog.Error("Failed to load poll", "error", err.Error())
+		return nil, err
+	}
+	poll, ok := polls[messageId]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	payload := &dto.PollUpdatedPayload{
+		MessageId:   poll.MessageId,
+		Tallies:     poll.Tallies,
+		TotalVoters: poll.TotalVoters,
+		ClosedAt:    poll.ClosedAt,
+	}
+	if poll.IsClosed(time.Now()) {
+		payload.CorrectOption = poll.CorrectOption
+		payload.Explanation = poll.Explanation
+	}
+	s.publishEvent(dto.EventPollUpdated, poll.ChatId, nil, payload)
+	return poll, nil
+}
+
+func (s *ChatManagementService) loadPolls(messageIds []uuid.UUID, viewerId uuid.UUID) (map[uuid.UUID]*models.Poll, error) {
+	polls, err := s.repo.GetPolls(messageIds)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID]*models.Poll, len(polls))
+	if len(polls) == 0 {
+		return result, nil
+	}
+	tallies, err := s.repo.GetPollTallies(messageIds)
+	if err != nil {
+		return nil, err
+	}
+	voters, err := s.repo.CountPollVoters(messageIds)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := s.repo.GetPollVotes(messageIds, &viewerId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range polls {
+		poll := &polls[i]
+		poll.Tallies = make([]int, len(poll.Options))
+		poll.TotalVoters = voters[poll.MessageId]
+		poll.Voted = []int{}
+		result[poll.MessageId] = poll
+	}
+	for _, tally := range tallies {
+		poll := result[tally.MessageId]
+		if tally.Option < len(poll.Tallies) {
+			poll.Tallies[tally.Option] = tally.Count
+		}
+	}
+	for _, vote := range votes {
+		result[vote.MessageId].Voted = append(result[vote.MessageId].Voted, vote.Option)
+	}
+	now := time.Now()
+	for _, poll := range result {
+		if !revealsAnswer(poll, now) {
+			poll.CorrectOption = nil
+			poll.Explanation = ""
+		}
+	}
+	return result, nil
+}
+
+func (s *ChatManagementService) fillPolls(messages []models.Message, viewerId uuid.UUID) error {
+	messageIds := make([]uuid.UUID, 0)
+	for _, message := range messages {
+		if message.Type == models.MessageTypePoll {
+			messageIds = append(messageIds, message.Id)
+		}
+	}
+	if len(messageIds) == 0 {
+		return nil
+	}
+	polls, err := s.loadPolls(messageIds, viewerId)
+	if err != nil {
+		return err
+	}
+	for i := range messages {
+		messages[i].Poll = polls[messages[i].Id]
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+)
+
+func TestCheckPollOptions(t *testing.T) {
+	single := &models.Poll{Options: []string{"a", "b", "c"}}
+	multiple := &models.Poll{Options: []string{"a", "b", "c"}, MultipleChoice: true}
+
+	tests := []struct {
+		name    string
+		poll    *models.Poll
+		options []int
+		want    []int
+		wantErr error
+	}{
+		{"single option", single, []int{1}, []int{1}, nil},
+		{"no option", single, nil, nil, ErrInvalidPollOption},
+		{"negative option", single, []int{-1}, nil, ErrInvalidPollOption},
+		{"option out of range", single, []int{3}, nil, ErrInvalidPollOption},
+		{"repeated option counts once", single, []int{2, 2}, []int{2}, nil},
+		{"several options in single choice", single, []int{0, 1}, nil, ErrSingleChoice},
+		{"several options in multiple choice", multiple, []int{2, 0}, []int{0, 2}, nil},
+		{"out of range in multiple choice", multiple, []int{0, 5}, nil, ErrInvalidPollOption},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkPollOptions(tt.poll, tt.options)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkPollOptions() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("checkPollOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevealsAnswer(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name string
+		poll *models.Poll
+		want bool
+	}{
+		{"regular poll", &models.Poll{}, true},
+		{"open quiz before answering", &models.Poll{Quiz: true, ClosesAt: &future}, false},
+		{"open quiz after answering", &models.Poll{Quiz: true, Voted: []int{0}}, true},
+		{"closed quiz", &models.Poll{Quiz: true, ClosedAt: &past}, true},
+		{"quiz past its close time", &models.Poll{Quiz: true, ClosesAt: &past}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := revealsAnswer(tt.poll, now); got != tt.want {
+				t.Fatalf("revealsAnswer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReactionNotAllowed = errors.New("reaction is not allowed in this chat")
+	ErrReadersCannotReact = errors.New("readers cannot react in this chat")
+)
+
+func (s *ChatManagementService) AddReaction(userId uuid.UUID, req *dto.ReactionRequest) ([]models.ReactionCount, error) {
+	chat, message, err := s.checkCanReact(userId, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(chat.AllowedReactions) > 0 && !slices.Contains(chat.AllowedReactions, req.Emoji) {
+		return nil, ErrReactionNotAllowed
+	}
+	err = s.repo.AddReaction(&models.Reaction{MessageId: message.Id, UserId: userId, Emoji: req.Emoji})
+	if err != nil {
+		slog.Error("Failed to add reaction", "error", err.Error())
+		return nil, err
+	}
+	return s.reactionsChanged(message, userId)
+}
+
+// Members who may no longer react can still take back their own reactions.
+func (s *ChatManagementService) RemoveReaction(userId uuid.UUID, req *dto.ReactionRequest) ([]models.ReactionCount, error) {
+	_, err := s.getMembership(req.ChatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.findChatMessage(req.ChatId, req.MessageId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.RemoveReaction(message.Id, userId, req.Emoji)
+	if err != nil {
+		slog.Error("Failed to remove reaction", "error", err.Error())
+		return nil, err
+	}
+	return s.reactionsChanged(message, userId)
+}
+
+func (s *ChatManagementService) GetReactions(chatId uuid.UUID, messageId uuid.UUID, userId uuid.UUID) ([]models.ReactionCount, error) {
+	_, err := s.getMembership(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.findChatMessage(chatId, messageId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetReactionCounts([]uuid.UUID{message.Id}, userId)
+}
+
+func (s *ChatManagementService) checkCanReact(userId uuid.UUID, req *dto.ReactionRequest) (*models.Chat, *models.Message, error) {
+	chat, _, err := s.authorizeIn(req.ChatId, userId, ActionReact)
+	if err != nil {
+		return nil, nil, err
+	}
+	message, err := s.findChatMessage(req.ChatId, req.MessageId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chat, message, nil
+}
+
+// The broadcast counts carry no viewer flag, clients track their own
+// reactions.
+func (s *ChatManagementService) reactionsChanged(message *models.Message, userId uuid.UUID) ([]models.ReactionCount, error) {
+	counts, err := s.repo.GetReactionCounts([]uuid.UUID{message.Id}, userId)
+	if err != nil {
+		return nil, err
+	}
+	broadcast := make([]models.ReactionCount, len(counts))
+	for i, count := range counts {
+		broadcast[i] = count
+		broadcast[i].Reacted = false
+	}
+	s.publishEvent(dto.EventReactions, message.ChatId, nil, &dto.ReactionsPayload{MessageId: message.Id, Reactions: broadcast})
+	return counts, nil
+}
+
+func (s *ChatManagementService) fillReactions(messages []models.Message, viewerId uuid.UUID) error {
+	messageIds := make([]uuid.UUID, 0, len(messages))
+	for _, message := range messages {
+		messageIds = append(messageIds, message.Id)
+	}
+	counts, err := s.repo.GetReactionCounts(messageIds, viewerId)
+	if err != nil {
+		return err
+	}
+	byMessage := make(map[uuid.UUID][]models.ReactionCount)
+	for _, count := range counts {
+		byMessage[count.MessageId] = append(byMessage[count.MessageId], count)
+	}
+	for i := range messages {
+		messages[i].Reactions = byMessage[messages[i].Id]
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/google/uuid"
+)
+
+const lastMessagePreviewLength = 100
+
+func (s *ChatManagementService) MarkRead(userId uuid.UUID, req *dto.MarkReadRequest) error {
+	chat, err := s.repo.FindById(req.ChatId)
+	if err != nil {
+		return err
+	}
+	_, err = s.getMembership(req.ChatId, userId)
+	if err != nil {
+		return err
+	}
+	message, err := s.findChatMessage(req.ChatId, req.MessageId)
+	if err != nil {
+		return err
+	}
+	advanced, err := s.repo.AdvanceReadPosition(req.ChatId, userId, message.Id, message.CreatedAt)
+	if err != nil {
+		slog.Error("Failed to advance read position", "error", err.Error())
+		return err
+	}
+	if advanced && !chat.IsChannel {
+		s.publishEvent(dto.EventMessagesRead, req.ChatId, []uuid.UUID{userId}, &dto.MessagesReadPayload{UserId: userId, MessageId: message.Id})
+	}
+	return nil
+}
+
+// Unread counts and last message previews of the user's chats, keyed by chat
+// id. Chats the user is not a member of count nothing.
+func (s *ChatManagementService) GetChatReadStates(chatIds []uuid.UUID, userId uuid.UUID) (map[uuid.UUID]int, map[uuid.UUID]*dto.LastMessagePreview, error) {
+	unread, err := s.repo.CountUnreadByChat(chatIds, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	messages, err := s.repo.FindLastMessages(chatIds, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	previews := make(map[uuid.UUID]*dto.LastMessagePreview, len(messages))
+	for _, message := range messages {
+		preview := &dto.LastMessagePreview{
+			Id:             message.Id,
+			SenderId:       message.SenderId,
+			Text:           message.Text,
+			HasAttachments: len(message.Attachments) > 0,
+			CreatedAt:      message.CreatedAt,
+		}
+		if utf8.RuneCountInString(preview.Text) > lastMessagePreviewLength {
+			preview.Text = string([]rune(preview.Text)[:lastMessagePreviewLength])
+		}
+		previews[message.ChatId] = preview
+	}
+	return unread, previews, nil
+}
+
+// Groups list who has read the message, channels only expose a view count.
+func (s *ChatManagementService) GetMessageReads(chatId uuid.UUID, messageId uuid.UUID, userId uuid.UUID) (*dto.MessageReadsResponse, error) {
+	chat, err := s.repo.FindById(chatId)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.getMembership(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.findChatMessage(chatId, messageId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.MessageReadsResponse{MessageId: message.Id}
+	if chat.IsChannel {
+		resp.Views, err = s.repo.CountMessageViews(chatId, message.CreatedAt, message.SenderId)
+		return resp, err
+	}
+	readers, err := s.repo.GetMessageReaders(chatId, message.CreatedAt, message.SenderId)
+	if err != nil {
+		return nil, err
+	}
+	resp.ReadBy = make([]uuid.UUID, 0, len(readers))
+	for _, reader := range readers {
+		resp.ReadBy = append(resp.ReadBy, reader.UserId)
+	}
+	resp.Views = len(resp.ReadBy)
+	return resp, nil
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	ErrBanned        = errors.New("user is banned from the chat")
+	ErrRestrictSelf  = errors.New("cannot restrict yourself")
+	ErrChannelMute   = errors.New("channel members are readers already")
+	ErrAlreadyReader = errors.New("member is already a reader")
+	ErrMuteAdmin     = errors.New("admins must be demoted before they can be muted")
+)
+
+const restrictionsBatch = 100
+
+func (s *ChatManagementService) BanMember(actorId uuid.UUID, req *dto.RestrictMemberRequest) (*models.ChatRestriction, error) {
+	chat, actor, err := s.authorizeGroupAction(req.ChatId, actorId, ActionBanUsers)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserId == actorId {
+		return nil, ErrRestrictSelf
+	}
+	target, err := s.repo.FindUserChat(chat.Id, req.UserId)
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+	if err != nil {
+		target = nil
+	}
+	err = authorizeOn(chat, actor, target, ActionBanUsers)
+	if err != nil {
+		return nil, err
+	}
+	var restriction *models.ChatRestriction
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		var err error
+		restriction, err = restrict(tx, chat.Id, actorId, models.RestrictionBan, req)
+		if err != nil || target == nil {
+			return err
+		}
+		return tx.RemoveUserFromChat(target)
+	})
+	if err != nil {
+		slog.Error("Failed to ban member", "error", err.Error())
+		return nil, err
+	}
+	if target != nil {
+		s.publishEvent(dto.EventMembersRemoved, chat.Id, []uuid.UUID{req.UserId}, nil)
+	}
+	s.publishEvent(dto.EventMemberBanned, chat.Id, []uuid.UUID{req.UserId}, restriction)
+	return restriction, nil
+}
+
+func (s *ChatManagementService) MuteMember(actorId uuid.UUID, req *dto.RestrictMemberRequest) (*models.ChatRestriction, error) {
+	chat, actor, err := s.authorizeGroupAction(req.ChatId, actorId, ActionBanUsers)
+	if err != nil {
+		return nil, err
+	}
+	if chat.IsChannel {
+		return nil, ErrChannelMute
+	}
+	if req.UserId == actorId {
+		return nil, ErrRestrictSelf
+	}
+	target, err := s.getMembership(chat.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = authorizeOn(chat, actor, target, ActionBanUsers)
+	if err != nil {
+		return nil, err
+	}
+	if target.IsAdmin {
+		return nil, ErrMuteAdmin
+	}
+	if target.ReadOnly {
+		_, err = s.repo.FindActiveRestriction(chat.Id, req.UserId, models.RestrictionMute, time.Now())
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrAlreadyReader
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	var restriction *models.ChatRestriction
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		var err error
+		restriction, err = restrict(tx, chat.Id, actorId, models.RestrictionMute, req)
+		if err != nil {
+			return err
+		}
+		target.ReadOnly = true
+		return tx.UpdateUserChat(target)
+	})
+	if err != nil {
+		slog.Error("Failed to mute member", "error", err.Error())
+		return nil, err
+	}
+	s.publishEvent(dto.EventMemberMuted, chat.Id, []uuid.UUID{req.UserId}, restriction)
+	return restriction, nil
+}
+
+func (s *ChatManagementService) UnbanMember(actorId uuid.UUID, req *dto.LiftRestrictionRequest) error {
+	return s.lift(actorId, req, models.RestrictionBan)
+}
+
+func (s *ChatManagementService) UnmuteMember(actorId uuid.UUID, req *dto.LiftRestrictionRequest) error {
+	return s.lift(actorId, req, models.RestrictionMute)
+}
+
+func (s *ChatManagementService) GetRestrictions(chatId uuid.UUID, actorId uuid.UUID) ([]models.ChatRestriction, error) {
+	_, _, err := s.authorizeIn(chatId, actorId, ActionManageChat)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetActiveRestrictions(chatId, time.Now())
+}
+
+// Lifts restrictions whose time is up until ctx is cancelled.
+func (s *ChatManagementService) RunRestrictionSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweepRestrictions()
+		}
+	}
+}
+
+func (s *ChatManagementService) sweepRestrictions() {
+	for {
+		restrictions, err := s.repo.GetExpiredRestrictions(time.Now(), restrictionsBatch)
+		if err != nil {
+			slog.Error("Failed to get expired restrictions", "error", err.Error())
+			return
+		}
+		// Failed rows stay expired, so paging stops after them and the next
+		// tick retries.
+		failed := false
+		for i := range restrictions {
+			err = s.liftRestriction(&restrictions[i])
+			if err != nil {
+				slog.Error("Failed to lift restriction", "error", err.Error(), "restrictionId", restrictions[i].Id)
+				failed = true
+			}
+		}
+		if failed || len(restrictions) < restrictionsBatch {
+			return
+		}
+	}
+}
+
+func (s *ChatManagementService) checkNotBanned(chatId uuid.UUID, userId uuid.UUID) error {
+	_, err := s.repo.FindActiveRestriction(chatId, userId, models.RestrictionBan, time.Now())
+	if err == nil {
+		return ErrBanned
+	}
+	if gorm.IsRecordNotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+// A repeated ban or mute replaces the terms of the active one.
+func restrict(tx *repository.ChatRepository, chatId uuid.UUID, actorId uuid.UUID, kind string, req *dto.RestrictMemberRequest) (*models.ChatRestriction, error) {
+	restriction, err := tx.FindActiveRestriction(chatId, req.UserId, kind, time.Now())
+	if gorm.IsRecordNotFoundError(err) {
+		restriction = &models.ChatRestriction{
+			Id:     uuid.New(),
+			ChatId: chatId,
+			UserId: req.UserId,
+			Kind:   kind,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	restriction.Reason = req.Reason
+	restriction.CreatedBy = actorId
+	restriction.ExpiresAt = req.Until
+	err = tx.SaveRestriction(restriction)
+	if err != nil {
+		return nil, err
+	}
+	return restriction, nil
+}
+
+func (s *ChatManagementService) lift(actorId uuid.UUID, req *dto.LiftRestrictionRequest, kind string) error {
+	_, _, err := s.authorizeGroupAction(req.ChatId, actorId, ActionBanUsers)
+	if err != nil {
+		return err
+	}
+	restriction, err := s.repo.FindActiveRestriction(req.ChatId, req.UserId, kind, time.Now())
+	if err != nil {
+		return err
+	}
+	return s.liftRestriction(restriction)
+}
+
+// Returns the lifted mute, or nil when the member was not muted.
+func liftMuteTx(tx *repository.ChatRepository, chatId uuid.UUID, userId uuid.UUID) (*models.ChatRestriction, error) {
+	now := time.Now()
+	restriction, err := tx.FindActiveRestriction(chatId, userId, models.RestrictionMute, now)
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lifted, err := tx.LiftRestriction(restriction.Id, now)
+	if err != nil || !lifted {
+		return nil, err
+	}
+	restriction.LiftedAt = &now
+	return restriction, nil
+}
+
+func (s *ChatManagementService) liftRestriction(restriction *models.ChatRestriction) error {
+	now := time.Now()
+	lifted := false
+	err := s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		var err error
+		lifted, err = tx.LiftRestriction(restriction.Id, now)
+		if err != nil || !lifted || restriction.Kind != models.RestrictionMute {
+			return err
+		}
+		userChat, err := tx.FindUserChat(restriction.ChatId, restriction.UserId)
+		if gorm.IsRecordNotFoundError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		userChat.ReadOnly = false
+		return tx.UpdateUserChat(userChat)
+	})
+	if err != nil || !lifted {
+		return err
+	}
+	restriction.LiftedAt = &now
+	event := dto.EventMemberUnbanned
+	if restriction.Kind == models.RestrictionMute {
+		event = dto.EventMemberUnmuted
+	}
+	s.publishEvent(event, restriction.ChatId, []uuid.UUID{restriction.UserId}, restriction)
+	return nil
+}
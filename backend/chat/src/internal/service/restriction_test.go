@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/google/uuid"
+)
+
+func TestMakingMutedMemberReaderLiftsMute(t *testing.T) {
+	s, _ := newTestService(t)
+	chat := createTestChat(t, s, 1)
+	memberId := uuid.New()
+	err := s.AddUsers(chat.Id, chat.CreatorId, []uuid.UUID{memberId})
+	if err != nil {
+		t.Fatalf("failed to add member: %v", err)
+	}
+	until := time.Now().Add(time.Hour)
+	_, err = s.MuteMember(chat.CreatorId, &dto.RestrictMemberRequest{ChatId: chat.Id, UserId: memberId, Until: &until})
+	if err != nil {
+		t.Fatalf("failed to mute member: %v", err)
+	}
+
+	err = s.MakeUsersReaders(chat.Id, chat.CreatorId, []uuid.UUID{memberId})
+	if err != nil {
+		t.Fatalf("failed to make member a reader: %v", err)
+	}
+
+	restrictions, err := s.GetRestrictions(chat.Id, chat.CreatorId)
+	if err != nil {
+		t.Fatalf("failed to get restrictions: %v", err)
+	}
+	if len(restrictions) != 0 {
+		t.Fatalf("expected the mute to be lifted, got %d active restrictions", len(restrictions))
+	}
+	member, err := s.getMembership(chat.Id, memberId)
+	if err != nil {
+		t.Fatalf("failed to get member: %v", err)
+	}
+	if !member.ReadOnly {
+		t.Fatal("expected the member to stay a reader")
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
+	"github.com/google/uuid"
+)
+
+const reindexBatchSize = 1000
+
+var ErrEmptySearchQuery = errors.New("search query is empty")
+
+// Search within one chat when chatId is set, otherwise across every chat the
+// user belongs to.
+func (s *ChatManagementService) SearchMessages(userId uuid.UUID, chatId *uuid.UUID, req *dto.SearchMessagesRequest) (*dto.SearchMessagesResponse, error) {
+	if req.Query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	var chatIds []uuid.UUID
+	if chatId != nil {
+		_, err := s.getMembership(*chatId, userId)
+		if err != nil {
+			return nil, err
+		}
+		chatIds = []uuid.UUID{*chatId}
+	} else {
+		userChats, err := s.repo.GetChatsForUser(userId)
+		if err != nil {
+			return nil, err
+		}
+		for _, userChat := range userChats {
+			chatIds = append(chatIds, userChat.ChatId)
+		}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultMessagesLimit
+	}
+	if limit > MaxMessagesLimit {
+		limit = MaxMessagesLimit
+	}
+	var after *repository.MessageSearchPosition
+	if req.Cursor != "" {
+		position, err := decodeSearchCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = position
+	}
+	rows, err := s.repo.SearchMessages(&repository.MessageSearchFilter{
+		ChatIds:       chatIds,
+		ViewerId:      userId,
+		Query:         req.Query,
+		SenderId:      req.SenderId,
+		From:          req.From,
+		To:            req.To,
+		HasAttachment: req.HasAttachment,
+		After:         after,
+		Limit:         limit + 1,
+	}, s.searchLanguage)
+	if err != nil {
+		slog.Error("Failed to search messages", "error", err.Error())
+		return nil, err
+	}
+	resp := &dto.SearchMessagesResponse{Results: make([]*dto.SearchResult, 0, len(rows))}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		resp.NextCursor = encodeSearchCursor(last.Rank, last.CreatedAt, last.Id)
+	}
+	for _, row := range rows {
+		resp.Results = append(resp.Results, &dto.SearchResult{Message: row.Message, Rank: row.Rank, Snippet: highlightSnippet(row.Snippet)})
+	}
+	return resp, nil
+}
+
+var snippetMarkers = strings.NewReplacer("\x01", "<b>", "\x02", "</b>")
+
+// The snippet is raw message text, so it is escaped before the match markers
+// are turned into tags.
+func highlightSnippet(snippet string) string {
+	return snippetMarkers.Replace(html.EscapeString(snippet))
+}
+
+func encodeSearchCursor(rank float64, createdAt time.Time, id uuid.UUID) string {
+	raw := fmt.Sprintf("%s_%d_%s", strconv.FormatFloat(rank, 'g', -1, 64), createdAt.UnixNano(), id.String())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSearchCursor(cursor string) (*repository.MessageSearchPosition, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "_", 3)
+	if len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+	rank, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	unixNano, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &repository.MessageSearchPosition{Rank: rank, CreatedAt: time.Unix(0, unixNano), Id: id}, nil
+}
+
+func (s *ChatManagementService) ReindexMessages() error {
+	afterId := uuid.Nil
+	total := 0
+	for {
+		lastId, count, err := s.repo.ReindexMessages(afterId, reindexBatchSize, s.searchLanguage)
+		if err != nil {
+			slog.Error("Failed to reindex messages", "error", err.Error(), "after", afterId)
+			return err
+		}
+		if count == 0 {
+			break
+		}
+		total += count
+		afterId = lastId
+		slog.Info("Reindexed messages", "total", total)
+	}
+	slog.Info("Reindex finished", "total", total)
+	return nil
+}
+
+func (s *ChatManagementService) indexMessage(messageId uuid.UUID) {
+	err := s.repo.IndexMessage(messageId, s.searchLanguage)
+	if err != nil {
+		slog.Error("Failed to index message", "error", err.Error(), "messageId", messageId)
+	}
+}
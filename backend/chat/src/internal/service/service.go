@@ -3,20 +3,29 @@ package service
 import (
 	"errors"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
 	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
 )
 
 type ChatManagementService struct {
-	repo repository.ChatRepository
+	repo              repository.ChatRepository
+	messageEditWindow time.Duration
+	searchLanguage    string
+	limits            MemberLimits
 }
 
-func New(repo repository.ChatRepository) *ChatManagementService {
+func New(repo repository.ChatRepository, messageEditWindow time.Duration, searchLanguage string, limits MemberLimits) *ChatManagementService {
 	return &ChatManagementService{
-		repo: repo,
+		repo:              repo,
+		messageEditWindow: messageEditWindow,
+		searchLanguage:    searchLanguage,
+		limits:            limits,
 	}
 }
 
@@ -26,7 +35,20 @@ func (s *ChatManagementService) GetChat(chatId uuid.UUID) (*dto.GetChatResponse,
 		slog.Error("Failed to get chat", "error", err.Error())
 		return nil, err
 	}
-	userChats, err := s.repo.GetChatUsers(chatId)
+	membersCount, err := s.repo.CountChatMembers(chatId)
+	if err != nil {
+		slog.Error("Failed to count chat members", "error", err.Error())
+		return nil, err
+	}
+	// Supergroups and channels only list their admins, members are paged
+	// through GetMembers.
+	adminsOnly := chat.IsSupergroup || chat.IsChannel
+	var userChats []models.UserChat
+	if adminsOnly {
+		userChats, err = s.repo.GetChatAdmins(chatId)
+	} else {
+		userChats, err = s.repo.GetChatUsers(chatId)
+	}
 	if err != nil {
 		slog.Error("Failed to get chat users", "error", err.Error())
 		return nil, err
@@ -34,66 +56,118 @@ func (s *ChatManagementService) GetChat(chatId uuid.UUID) (*dto.GetChatResponse,
 	var users []string
 	var admins []string
 	var readers []string
+	adminRights := make(map[string]models.AdminRight)
 	for _, userChat := range userChats {
-		users = append(users, userChat.UserId.String())
+		if !adminsOnly {
+			users = append(users, userChat.UserId.String())
+		}
 		if userChat.IsAdmin {
 			admins = append(admins, userChat.UserId.String())
+			adminRights[userChat.UserId.String()] = userChat.AdminRights
 		} else if userChat.ReadOnly {
 			readers = append(readers, userChat.UserId.String())
 		}
 	}
+	threadsCount, err := s.repo.CountThreads(chatId)
+	if err != nil {
+		slog.Error("Failed to count threads", "error", err.Error())
+		return nil, err
+	}
+	pins, err := s.pins(chatId, 1)
+	if err != nil {
+		return nil, err
+	}
+	var latestPin *dto.Pin
+	if len(pins) > 0 {
+		latestPin = &pins[0]
+	}
 	getResp := &dto.GetChatResponse{
-		Chat:    chat,
-		Users:   users,
-		Admins:  admins,
-		Readers: readers,
+		Chat:         chat,
+		Users:        users,
+		Admins:       admins,
+		Readers:      readers,
+		AdminRights:  adminRights,
+		ThreadsCount: threadsCount,
+		MembersCount: membersCount,
+		LatestPin:    latestPin,
 	}
 	return getResp, nil
 }
 
-func (s *ChatManagementService) CreateChat(req *dto.CreateChatRequest) (*dto.GetChatResponse, error) {
-	slog.Info("CreateChat called", "name", req.Name, "description", req.Description, "creatorID", req.CreatorId)
+func (s *ChatManagementService) CreateChat(creatorId uuid.UUID, req *dto.CreateChatRequest) (*dto.GetChatResponse, error) {
+	slog.Info("CreateChat called", "name", req.Name, "description", req.Description, "creatorID", creatorId)
 	chat := &models.Chat{
-		Id:          uuid.New(),
-		Name:        req.Name,
-		CreatorId:   req.CreatorId,
-		IsChannel:   req.IsChannel,
-		Description: req.Description,
-		ProfilePic:  "",
-	}
-	err := s.repo.SaveChat(chat)
-	if err != nil {
-		slog.Error("Failed to create chat", "error", err.Error())
-		return nil, err
+		Id:                uuid.New(),
+		Name:              req.Name,
+		CreatorId:         creatorId,
+		IsChannel:         req.IsChannel,
+		JoinLink:          newInviteToken(),
+		MemberPermissions: models.DefaultMemberPermissions,
+		Description:       req.Description,
+		ProfilePic:        "",
+	}
+	participantIds := []uuid.UUID{}
+	for _, participantId := range req.ParticipantsIds {
+		if participantId != creatorId && !slices.Contains(participantIds, participantId) {
+			participantIds = append(participantIds, participantId)
+		}
 	}
-	creatorChat := models.UserChat{
-		ChatId:   chat.Id,
-		UserId:   req.CreatorId,
-		IsAdmin:  true,
-		ReadOnly: false,
+	if limit := s.limits.of(chat); limit > 0 && len(participantIds)+1 > limit {
+		return nil, ErrChatFull
 	}
-	err = s.repo.AddUserToChat(&creatorChat)
+	err := s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		err := tx.SaveChat(chat)
+		if err != nil {
+			return err
+		}
+		err = tx.SaveInviteLink(&models.InviteLink{
+			Id:        uuid.New(),
+			ChatId:    chat.Id,
+			Token:     chat.JoinLink,
+			Name:      PrimaryInviteLinkName,
+			CreatorId: creatorId,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tx.InsertUserChat(&models.UserChat{
+			ChatId:      chat.Id,
+			UserId:      creatorId,
+			IsAdmin:     true,
+			AdminRights: models.AllAdminRights,
+		})
+		if err != nil {
+			return err
+		}
+		for _, participantId := range participantIds {
+			_, err = tx.InsertUserChat(&models.UserChat{
+				ChatId:    chat.Id,
+				UserId:    participantId,
+				ReadOnly:  req.IsChannel,
+				InvitedBy: &creatorId,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		slog.Error("Failed to add user to chat", "error", err.Error())
+		slog.Error("Failed to create chat", "error", err.Error())
 		return nil, err
 	}
 	readers := []string{}
 	users := []string{}
-	admins := []string{req.CreatorId.String()}
-	for _, participantId := range req.ParticipantsIds {
-		userChat := models.UserChat{
-			ChatId:   chat.Id,
-			UserId:   participantId,
-			IsAdmin:  false,
-			ReadOnly: req.IsChannel,
-		}
-		err = s.repo.AddUserToChat(&userChat)
-		if err != nil {
-			slog.Error("Failed to add user to chat", "error", err.Error())
-			return nil, err
-		}
+	admins := []string{creatorId.String()}
+	for _, participantId := range participantIds {
 		readers = append(readers, participantId.String())
 	}
+	s.publishEvent(dto.EventChatCreated, chat.Id, append([]uuid.UUID{creatorId}, participantIds...), chat)
+	s.postServiceMessage(chat.Id, creatorId, &models.ServiceAction{
+		Type:    models.ServiceMessageChatCreated,
+		Title:   chat.Name,
+		UserIds: participantIds,
+	})
 	return &dto.GetChatResponse{Chat: chat, Admins: admins, Readers: readers, Users: users}, nil
 }
 
@@ -103,195 +177,263 @@ func (s *ChatManagementService) DeleteChat(chatId uuid.UUID) error {
 		slog.Error("Failed to delete chat", "error", err.Error())
 		return err
 	}
+	s.publishEvent(dto.EventChatDeleted, chatId, nil, nil)
 	return nil
 }
 
-func (s *ChatManagementService) UpdateChat(req *dto.UpdateChatRequest) error {
-	chat, err := s.repo.FindById(req.ChatId)
+func (s *ChatManagementService) UpdateChat(actorId uuid.UUID, req *dto.UpdateChatRequest) error {
+	chat, actor, err := s.authorizeGroupAction(req.ChatId, actorId, ActionChangeInfo)
 	if err != nil {
-		slog.Error("Failed to find chat", "error", err.Error())
 		return err
 	}
+	if req.MemberPermissions != nil {
+		err = authorize(chat, actor, ActionBanUsers)
+		if err != nil {
+			return err
+		}
+		chat.MemberPermissions = *req.MemberPermissions & models.AllPermissions
+	}
+	previousName, previousPic := chat.Name, chat.ProfilePic
 	chat.Name = req.Name
 	chat.Description = req.Description
 	chat.ProfilePic = req.ProfilePic
+	if req.AllowedReactions != nil {
+		chat.AllowedReactions = *req.AllowedReactions
+	}
+	if req.ReadersCanReact != nil {
+		chat.ReadersCanReact = *req.ReadersCanReact
+	}
+	if req.ReadersCanVote != nil {
+		chat.ReadersCanVote = *req.ReadersCanVote
+	}
 	err = s.repo.UpdateChat(chat)
 	if err != nil {
 		slog.Error("Failed to update chat", "error", err.Error())
 		return err
 	}
+	s.publishEvent(dto.EventChatUpdated, chat.Id, nil, chat)
+	if chat.Name != previousName {
+		s.postServiceMessage(chat.Id, actorId, &models.ServiceAction{Type: models.ServiceMessageTitleChanged, Title: chat.Name})
+	}
+	if chat.ProfilePic != previousPic {
+		s.postServiceMessage(chat.Id, actorId, &models.ServiceAction{Type: models.ServiceMessagePhotoChanged, Photo: chat.ProfilePic})
+	}
 	return nil
 }
 
-func (s *ChatManagementService) JoinChat(joinLink string, userId uuid.UUID) (*models.Chat, error) {
-	chat, err := s.repo.FindByJoinLink(joinLink)
+func (s *ChatManagementService) JoinChat(token string, userId uuid.UUID) (*dto.JoinChatResponse, error) {
+	chat, link, err := s.resolveInvite(token)
 	if err != nil {
-		slog.Error("Failed to find chat by join link", "error", err.Error())
+		slog.Error("Failed to resolve invite link", "error", err.Error())
 		return nil, err
 	}
-	userChats, err := s.repo.GetChatUsers(chat.Id)
-	if err != nil {
-		slog.Error("Failed to get chat users", "error", err.Error())
+	_, err = s.repo.FindUserChat(chat.Id, userId)
+	if err == nil {
+		return &dto.JoinChatResponse{Chat: chat, Status: dto.JoinStatusJoined}, nil
+	}
+	if !gorm.IsRecordNotFoundError(err) {
 		return nil, err
 	}
-	if len(userChats) > 19 && !chat.IsChannel {
-		return nil, errors.New("chat is full")
+	err = s.checkNotBanned(chat.Id, userId)
+	if err != nil {
+		return nil, err
 	}
-	userChat := &models.UserChat{
-		ChatId:   chat.Id,
-		UserId:   userId,
-		ReadOnly: chat.IsChannel,
-		IsAdmin:  false,
+	if link != nil && link.RequiresApproval {
+		request, err := s.requestToJoin(chat, link, userId)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.JoinChatResponse{Chat: chat, Status: dto.JoinStatusPending, RequestId: &request.Id}, nil
 	}
-	err = s.repo.AddUserToChat(userChat)
+	err = s.joinViaInvite(chat, link, userId)
 	if err != nil {
-		slog.Error("Failed to add user to chat", "error", err.Error())
 		return nil, err
 	}
-	return chat, nil
+	return &dto.JoinChatResponse{Chat: chat, Status: dto.JoinStatusJoined}, nil
 }
 
-func (s *ChatManagementService) DeleteUsers(chatId uuid.UUID, userIds []uuid.UUID) error {
-	chat, err := s.repo.FindById(chatId)
+func (s *ChatManagementService) DeleteUsers(chatId uuid.UUID, actorId uuid.UUID, userIds []uuid.UUID) error {
+	chat, actor, err := s.authorizeGroupAction(chatId, actorId, ActionBanUsers)
 	if err != nil {
-		slog.Error("Failed to find chat", "error", err.Error())
 		return err
 	}
-	for _, userId := range userIds {
-		if chat.CreatorId == userId {
-			slog.Error("Cannot delete creator")
-			return errors.New("cannot delete creator")
-		}
-		userChats, err := s.repo.GetChatUsers(chatId)
-		if err != nil {
-			slog.Error("Failed to get chat users", "error", err.Error())
-			return err
-		}
-		if len(userChats)-len(userIds) < 2 && !chat.IsChannel {
-			return s.DeleteChat(chatId)
-		}
-		for _, userChat := range userChats {
-			if userChat.UserId == userId {
-				err = s.repo.RemoveUserFromChat(&userChat)
-				if err != nil {
-					slog.Error("Failed to remove user from chat", "error", err.Error())
-					return err
-				}
+	userChats, err := s.repo.GetChatUsers(chatId)
+	if err != nil {
+		slog.Error("Failed to get chat users", "error", err.Error())
+		return err
+	}
+	err = authorizeTargets(chat, actor, userChats, userIds, ActionBanUsers)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(userIds, chat.CreatorId) {
+		slog.Error("Cannot delete creator")
+		return errors.New("cannot delete creator")
+	}
+	if len(userChats)-len(userIds) < 2 && !chat.IsChannel {
+		return s.DeleteChat(chatId)
+	}
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		for i := range userChats {
+			if !slices.Contains(userIds, userChats[i].UserId) {
+				continue
+			}
+			err := tx.RemoveUserFromChat(&userChats[i])
+			if err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Failed to remove users from chat", "error", err.Error())
+		return err
 	}
+	s.publishEvent(dto.EventMembersRemoved, chatId, userIds, nil)
+	s.postMembershipChange(chat, actorId, &models.ServiceAction{Type: models.ServiceMessageMembersRemoved, UserIds: userIds})
 	return nil
 }
 
-func (s *ChatManagementService) AddUsers(chatId uuid.UUID, userIds []uuid.UUID) error {
-	chat, err := s.repo.FindById(chatId)
-	if err != nil {
-		slog.Error("Failed to find chat", "error", err.Error())
-		return err
-	}
-	userChats, err := s.repo.GetChatUsers(chat.Id)
+func (s *ChatManagementService) AddUsers(chatId uuid.UUID, actorId uuid.UUID, userIds []uuid.UUID) error {
+	chat, _, err := s.authorizeGroupAction(chatId, actorId, ActionAddMembers)
 	if err != nil {
-		slog.Error("Failed to get chat users", "error", err.Error())
 		return err
 	}
-	if len(userChats) > 19 && !chat.IsChannel {
-		return errors.New("chat is full")
-	}
 	for _, userId := range userIds {
-		userChat := models.UserChat{
-			ChatId:   chatId,
-			UserId:   userId,
-			ReadOnly: chat.IsChannel,
-			IsAdmin:  false,
-		}
-		err := s.repo.AddUserToChat(&userChat)
+		err = s.checkNotBanned(chatId, userId)
 		if err != nil {
-			slog.Error("Failed to add user to chat", "error", err.Error())
 			return err
 		}
 	}
-	return nil
-}
-
-func (s *ChatManagementService) MakeReadersUsers(chatId uuid.UUID, readersIds []uuid.UUID) error {
-	userChats, err := s.repo.GetChatUsers(chatId)
+	var added []uuid.UUID
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		var err error
+		_, added, err = s.addMembersTx(tx, chat.Id, userIds, &actorId)
+		return err
+	})
 	if err != nil {
-		slog.Error("Failed to get chat users", "error", err.Error())
 		return err
 	}
-	for _, readerId := range readersIds {
-		for _, userChat := range userChats {
-			if userChat.UserId == readerId {
-				userChat.ReadOnly = false
-				err = s.repo.UpdateUserChat(&userChat)
-				if err != nil {
-					slog.Error("Failed to update user chat", "error", err.Error())
-					return err
-				}
-			}
-		}
+	s.publishMembersAdded(chatId, added)
+	if len(added) > 0 {
+		s.postMembershipChange(chat, actorId, &models.ServiceAction{Type: models.ServiceMessageMembersAdded, UserIds: added})
 	}
 	return nil
 }
 
-func (s *ChatManagementService) MakeUsersReaders(chatId uuid.UUID, usersIds []uuid.UUID) error {
-	userChats, err := s.repo.GetChatUsers(chatId)
+func (s *ChatManagementService) MakeReadersUsers(chatId uuid.UUID, actorId uuid.UUID, readersIds []uuid.UUID) error {
+	return s.updateMembers(chatId, actorId, readersIds, ActionBanUsers, func(userChat *models.UserChat) {
+		userChat.ReadOnly = false
+	})
+}
+
+func (s *ChatManagementService) MakeUsersReaders(chatId uuid.UUID, actorId uuid.UUID, usersIds []uuid.UUID) error {
+	return s.updateMembers(chatId, actorId, usersIds, ActionBanUsers, func(userChat *models.UserChat) {
+		userChat.ReadOnly = true
+		userChat.IsAdmin = false
+	})
+}
+
+// Promotes members to admins with the given rights, or with every right the
+// actor can grant when rights is nil.
+func (s *ChatManagementService) AddAdmin(chatId uuid.UUID, actorId uuid.UUID, adminsIds []uuid.UUID, rights *models.AdminRight) error {
+	chat, actor, err := s.authorizeGroupAction(chatId, actorId, ActionPromoteMembers)
 	if err != nil {
-		slog.Error("Failed to get chat users", "error", err.Error())
 		return err
 	}
-	for _, userId := range usersIds {
-		for _, userChat := range userChats {
-			if userChat.UserId == userId {
-				userChat.ReadOnly = true
-				userChat.IsAdmin = false
-				err = s.repo.UpdateUserChat(&userChat)
-				if err != nil {
-					slog.Error("Failed to update user chat", "error", err.Error())
-					return err
-				}
-			}
+	granted := grantableRights(chat, actor)
+	if rights != nil {
+		if *rights&^granted != 0 {
+			return ErrMissingAdminRight
 		}
+		granted = *rights
 	}
+	err = s.updateMembers(chatId, actorId, adminsIds, ActionPromoteMembers, func(userChat *models.UserChat) {
+		userChat.IsAdmin = true
+		userChat.AdminRights = granted
+	})
+	if err != nil {
+		return err
+	}
+	s.postServiceMessage(chatId, actorId, &models.ServiceAction{Type: models.ServiceMessageAdminsAdded, UserIds: adminsIds})
 	return nil
 }
 
-func (s *ChatManagementService) AddAdmin(chatId uuid.UUID, adminsIds []uuid.UUID) error {
+func (s *ChatManagementService) DeleteAdmin(chatId uuid.UUID, actorId uuid.UUID, adminsIds []uuid.UUID) error {
+	return s.updateMembers(chatId, actorId, adminsIds, ActionPromoteMembers, func(userChat *models.UserChat) {
+		userChat.IsAdmin = false
+	})
+}
+
+func (s *ChatManagementService) updateMembers(chatId uuid.UUID, actorId uuid.UUID, userIds []uuid.UUID, action Action, update func(*models.UserChat)) error {
+	chat, actor, err := s.authorizeGroupAction(chatId, actorId, action)
+	if err != nil {
+		return err
+	}
 	userChats, err := s.repo.GetChatUsers(chatId)
 	if err != nil {
 		slog.Error("Failed to get chat users", "error", err.Error())
 		return err
 	}
-	for _, adminId := range adminsIds {
-		for _, userChat := range userChats {
-			if userChat.UserId == adminId {
-				userChat.IsAdmin = true
-				err = s.repo.UpdateUserChat(&userChat)
-				if err != nil {
-					slog.Error("Failed to update user chat", "error", err.Error())
-					return err
-				}
+	err = authorizeTargets(chat, actor, userChats, userIds, action)
+	if err != nil {
+		return err
+	}
+	// A role set by hand replaces an active mute, so the mute is lifted with
+	// it and its expiry cannot undo the new role later.
+	var unmuted []*models.ChatRestriction
+	err = s.repo.Transaction(func(tx *repository.ChatRepository) error {
+		unmuted = nil
+		for i := range userChats {
+			if !slices.Contains(userIds, userChats[i].UserId) {
+				continue
+			}
+			restriction, err := liftMuteTx(tx, chatId, userChats[i].UserId)
+			if err != nil {
+				return err
+			}
+			if restriction != nil {
+				unmuted = append(unmuted, restriction)
+			}
+			update(&userChats[i])
+			err = tx.UpdateUserChat(&userChats[i])
+			if err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Failed to update user chats", "error", err.Error())
+		return err
+	}
+	for _, restriction := range unmuted {
+		s.publishEvent(dto.EventMemberUnmuted, chatId, []uuid.UUID{restriction.UserId}, restriction)
 	}
+	s.publishEvent(dto.EventMembersUpdated, chatId, userIds, nil)
 	return nil
 }
 
-func (s *ChatManagementService) DeleteAdmin(chatId uuid.UUID, adminsIds []uuid.UUID) error {
-	userChats, err := s.repo.GetChatUsers(chatId)
+func (s *ChatManagementService) authorizeGroupAction(chatId uuid.UUID, actorId uuid.UUID, action Action) (*models.Chat, *models.UserChat, error) {
+	chat, actor, err := s.authorizeIn(chatId, actorId, action)
 	if err != nil {
-		slog.Error("Failed to get chat users", "error", err.Error())
-		return err
+		return nil, nil, err
+	}
+	if chat.IsDirect {
+		return nil, nil, ErrDirectChat
 	}
-	for _, adminId := range adminsIds {
-		for _, userChat := range userChats {
-			if userChat.UserId == adminId {
-				userChat.IsAdmin = false
-				err = s.repo.UpdateUserChat(&userChat)
-				if err != nil {
-					slog.Error("Failed to update user chat", "error", err.Error())
-					return err
-				}
+	return chat, actor, nil
+}
+
+func authorizeTargets(chat *models.Chat, actor *models.UserChat, userChats []models.UserChat, targetIds []uuid.UUID, action Action) error {
+	for _, targetId := range targetIds {
+		for i := range userChats {
+			if userChats[i].UserId != targetId {
+				continue
+			}
+			err := authorizeOn(chat, actor, &userChats[i], action)
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -304,14 +446,24 @@ func (s *ChatManagementService) GetAllChatsForUser(userId uuid.UUID) ([]*models.
 		slog.Error("Failed to get all chats for user", "error", err.Error())
 		return nil, err
 	}
-	chats := make([]*models.Chat, 0)
+	chatIds := make([]uuid.UUID, 0, len(userChats))
 	for _, userChat := range userChats {
-		chat, err := s.repo.FindById(userChat.ChatId)
-		if err != nil {
-			slog.Error("Failed to find chat", "error", err.Error())
-			return nil, err
+		chatIds = append(chatIds, userChat.ChatId)
+	}
+	chatsById, err := s.repo.FindByIds(chatIds)
+	if err != nil {
+		slog.Error("Failed to find chats", "error", err.Error())
+		return nil, err
+	}
+	chats := make([]*models.Chat, 0, len(chatsById))
+	for _, chatId := range chatIds {
+		if chat, ok := chatsById[chatId]; ok {
+			chats = append(chats, chat)
 		}
-		chats = append(chats, chat)
 	}
 	return chats, nil
 }
+
+func (s *ChatManagementService) CountChatThreads(chatIds []uuid.UUID) (map[uuid.UUID]int, error) {
+	return s.repo.CountChatThreads(chatIds)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+// Service messages are best effort like events: a failure is logged and the
+// change that caused it stands.
+func (s *ChatManagementService) postServiceMessage(chatId uuid.UUID, actorId uuid.UUID, action *models.ServiceAction) {
+	message := &models.Message{
+		Id:       uuid.New(),
+		ChatId:   chatId,
+		SenderId: actorId,
+		Type:     models.MessageTypeService,
+		Action:   action,
+	}
+	err := s.repo.SaveMessage(message)
+	if err != nil {
+		slog.Error("Failed to save service message", "error", err.Error(), "type", action.Type)
+		return
+	}
+	s.publishEvent(dto.EventMessageCreated, chatId, nil, message)
+}
+
+// Channels don't announce who joins or leaves, subscribers only see posts.
+func (s *ChatManagementService) postMembershipChange(chat *models.Chat, actorId uuid.UUID, action *models.ServiceAction) {
+	if chat.IsChannel {
+		return
+	}
+	s.postServiceMessage(chat.Id, actorId, action)
+}
+
+func (s *ChatManagementService) postJoined(chat *models.Chat, userIds []uuid.UUID) {
+	for _, userId := range userIds {
+		s.postMembershipChange(chat, userId, &models.ServiceAction{Type: models.ServiceMessageMemberJoined})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/google/uuid"
+)
+
+const (
+	signalLifetime   = 5 * time.Second
+	signalRateWindow = 2 * time.Second
+)
+
+var (
+	ErrUnknownSignal     = errors.New("signal kind is unknown")
+	ErrSignalRateLimited = errors.New("signals are sent too often")
+	ErrReadersCannotEmit = errors.New("readers cannot send signals")
+)
+
+// Signals are never stored, they only go out as gateway events which clients
+// drop after ExpiresAt.
+func (s *ChatManagementService) EmitSignal(userId uuid.UUID, req *dto.SignalRequest) error {
+	switch req.Kind {
+	case dto.SignalTyping, dto.SignalRecordingVoice, dto.SignalUploadingPhoto:
+	default:
+		return ErrUnknownSignal
+	}
+	_, _, err := s.authorizeIn(req.ChatId, userId, ActionEmitSignal)
+	if err != nil {
+		return err
+	}
+	acquired, err := s.repo.AcquireSignalSlot(req.ChatId, userId, signalRateWindow)
+	if err != nil {
+		slog.Error("Failed to rate limit signal", "error", err.Error())
+		return err
+	}
+	if !acquired {
+		return ErrSignalRateLimited
+	}
+	payload := &dto.SignalPayload{UserId: userId, Kind: req.Kind, ExpiresAt: time.Now().Add(signalLifetime)}
+	s.publishEvent(dto.EventSignal, req.ChatId, []uuid.UUID{userId}, payload)
+	return nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+var ErrNotThreadRoot = errors.New("message is not a thread root")
+
+func (s *ChatManagementService) findThreadRoot(chatId uuid.UUID, rootId uuid.UUID) (*models.Message, error) {
+	root, err := s.findChatMessage(chatId, rootId)
+	if err != nil {
+		return nil, err
+	}
+	if root.ThreadRootId != nil {
+		return nil, ErrNotThreadRoot
+	}
+	return root, nil
+}
+
+// Reading the first page of a thread marks it as read.
+func (s *ChatManagementService) GetThreadMessages(chatId uuid.UUID, rootId uuid.UUID, userId uuid.UUID, cursor string, limit int) (*dto.MessagesPageResponse, error) {
+	_, err := s.getMembership(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.findThreadRoot(chatId, rootId)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.getMessagesPage(chatId, userId, &root.Id, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	if cursor == "" {
+		follower, err := s.getThreadFollower(root, userId, false)
+		if err != nil {
+			return nil, err
+		}
+		follower.LastReadAt = time.Now()
+		err = s.repo.SaveThreadFollower(follower)
+		if err != nil {
+			slog.Error("Failed to mark thread as read", "error", err.Error())
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func (s *ChatManagementService) SetThreadFollowing(chatId uuid.UUID, rootId uuid.UUID, userId uuid.UUID, following bool) error {
+	_, err := s.getMembership(chatId, userId)
+	if err != nil {
+		return err
+	}
+	root, err := s.findThreadRoot(chatId, rootId)
+	if err != nil {
+		return err
+	}
+	follower, err := s.getThreadFollower(root, userId, following)
+	if err != nil {
+		return err
+	}
+	follower.Following = following
+	return s.repo.SaveThreadFollower(follower)
+}
+
+func (s *ChatManagementService) GetFollowedThreads(chatId uuid.UUID, userId uuid.UUID) ([]*dto.ThreadSummaryResponse, error) {
+	_, err := s.getMembership(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	followers, err := s.repo.GetFollowedThreads(chatId, userId)
+	if err != nil {
+		return nil, err
+	}
+	rootIds := make([]uuid.UUID, 0, len(followers))
+	for _, follower := range followers {
+		rootIds = append(rootIds, follower.RootMessageId)
+	}
+	replies, err := s.repo.CountThreadReplies(rootIds)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnreadThreadMessages(rootIds, userId)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*dto.ThreadSummaryResponse, 0, len(followers))
+	for _, follower := range followers {
+		summaries = append(summaries, &dto.ThreadSummaryResponse{
+			RootMessageId: follower.RootMessageId,
+			Replies:       replies[follower.RootMessageId],
+			UnreadCount:   unread[follower.RootMessageId],
+		})
+	}
+	return summaries, nil
+}
+
+func (s *ChatManagementService) autoFollowThread(root *models.Message, userId uuid.UUID) {
+	_, err := s.repo.FindThreadFollower(root.Id, userId)
+	if err == nil {
+		return
+	}
+	if !gorm.IsRecordNotFoundError(err) {
+		slog.Error("Failed to find thread follower", "error", err.Error())
+		return
+	}
+	follower := &models.ThreadFollower{RootMessageId: root.Id, UserId: userId, ChatId: root.ChatId, Following: true}
+	if userId == root.SenderId {
+		follower.LastReadAt = root.CreatedAt
+	} else {
+		follower.LastReadAt = time.Now()
+	}
+	err = s.repo.SaveThreadFollower(follower)
+	if err != nil {
+		slog.Error("Failed to follow thread", "error", err.Error())
+	}
+}
+
+func (s *ChatManagementService) getThreadFollower(root *models.Message, userId uuid.UUID, following bool) (*models.ThreadFollower, error) {
+	follower, err := s.repo.FindThreadFollower(root.Id, userId)
+	if gorm.IsRecordNotFoundError(err) {
+		return &models.ThreadFollower{RootMessageId: root.Id, UserId: userId, ChatId: root.ChatId, Following: following}, nil
+	}
+	return follower, err
+}
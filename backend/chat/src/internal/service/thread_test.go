@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestReadingThreadDoesNotFollowIt(t *testing.T) {
+	s, db := newTestService(t)
+	chat := createTestChat(t, s, 1)
+	readerId := uuid.New()
+	err := s.AddUsers(chat.Id, chat.CreatorId, []uuid.UUID{readerId})
+	if err != nil {
+		t.Fatalf("failed to add reader: %v", err)
+	}
+	root, err := s.SendMessage(chat.Id, chat.CreatorId, &dto.SendMessageRequest{ChatId: chat.Id, Text: "root"})
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+
+	_, err = s.GetThreadMessages(chat.Id, root.Id, readerId, "", 0)
+	if err != nil {
+		t.Fatalf("failed to read thread: %v", err)
+	}
+
+	var follower models.ThreadFollower
+	err = db.Where("root_message_id = ? AND user_id = ?", root.Id, readerId).First(&follower).Error
+	if err != nil {
+		t.Fatalf("expected the read position to be stored: %v", err)
+	}
+	if follower.Following {
+		t.Fatal("reading a thread followed it")
+	}
+	followed, err := s.GetFollowedThreads(chat.Id, readerId)
+	if err != nil {
+		t.Fatalf("failed to get followed threads: %v", err)
+	}
+	if len(followed) != 0 {
+		t.Fatalf("expected no followed threads, got %d", len(followed))
+	}
+}
+
+func TestUnfollowingUnfollowedThreadDoesNotFollowIt(t *testing.T) {
+	s, _ := newTestService(t)
+	chat := createTestChat(t, s, 1)
+	root, err := s.SendMessage(chat.Id, chat.CreatorId, &dto.SendMessageRequest{ChatId: chat.Id, Text: "root"})
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+
+	err = s.SetThreadFollowing(chat.Id, root.Id, chat.CreatorId, false)
+	if err != nil {
+		t.Fatalf("failed to unfollow thread: %v", err)
+	}
+
+	followed, err := s.GetFollowedThreads(chat.Id, chat.CreatorId)
+	if err != nil {
+		t.Fatalf("failed to get followed threads: %v", err)
+	}
+	if len(followed) != 0 {
+		t.Fatalf("expected no followed threads, got %d", len(followed))
+	}
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 func ValidateChatName(name string) error {
@@ -19,3 +20,111 @@ func ValidateChatName(name string) error {
 	}
 	return nil
 }
+
+func ValidateReaction(emoji string) error {
+	if strings.TrimSpace(emoji) == "" {
+		return fmt.Errorf("reaction is blank")
+	}
+	if len(emoji) > 32 {
+		return fmt.Errorf("reaction %s is too long", emoji)
+	}
+	for _, c := range emoji {
+		if c <= 0x20 {
+			return fmt.Errorf("reaction %s contains forbidden characters", emoji)
+		}
+	}
+	return nil
+}
+
+func ValidateAllowedReactions(reactions []string) error {
+	if len(reactions) > 50 {
+		return fmt.Errorf("too many allowed reactions")
+	}
+	for _, reaction := range reactions {
+		err := ValidateReaction(reaction)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidateMessage(text string, hasAttachments bool) error {
+	if strings.TrimSpace(text) == "" && !hasAttachments {
+		return fmt.Errorf("message is empty")
+	}
+	if len(text) > 4096 {
+		return fmt.Errorf("message text is too long")
+	}
+	return nil
+}
+
+func ValidateInviteLink(name string, maxUses int, expiresAt *time.Time) error {
+	if len(name) > 64 {
+		return fmt.Errorf("invite link name %s is too long", name)
+	}
+	for _, c := range name {
+		if c <= 0x1F {
+			return fmt.Errorf("invite link name %s contains forbidden characters", name)
+		}
+	}
+	if maxUses < 0 {
+		return fmt.Errorf("max uses must not be negative")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return fmt.Errorf("expiry must be in the future")
+	}
+	return nil
+}
+
+func ValidateRestriction(until *time.Time, reason string) error {
+	if until != nil && !until.After(time.Now()) {
+		return fmt.Errorf("restriction must end in the future")
+	}
+	if len(reason) > 512 {
+		return fmt.Errorf("reason is too long")
+	}
+	return nil
+}
+
+func ValidatePoll(question string, options []string, multipleChoice bool, quiz bool, correctOption *int, explanation string, closesAt *time.Time) error {
+	if strings.TrimSpace(question) == "" {
+		return fmt.Errorf("poll question is blank")
+	}
+	if len(question) > 300 {
+		return fmt.Errorf("poll question is too long")
+	}
+	if len(options) < 2 || len(options) > 10 {
+		return fmt.Errorf("poll must have 2 to 10 options")
+	}
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		if strings.TrimSpace(option) == "" {
+			return fmt.Errorf("poll option is blank")
+		}
+		if len(option) > 100 {
+			return fmt.Errorf("poll option %s is too long", option)
+		}
+		if seen[option] {
+			return fmt.Errorf("poll option %s is repeated", option)
+		}
+		seen[option] = true
+	}
+	if quiz {
+		if multipleChoice {
+			return fmt.Errorf("quiz cannot be multiple choice")
+		}
+		if correctOption == nil || *correctOption < 0 || *correctOption >= len(options) {
+			return fmt.Errorf("quiz needs a correct option")
+		}
+	} else if correctOption != nil || explanation != "" {
+		return fmt.Errorf("only quizzes have a correct option and explanation")
+	}
+	if len(explanation) > 200 {
+		return fmt.Errorf("explanation is too long")
+	}
+	if closesAt != nil && !closesAt.After(time.Now()) {
+		return fmt.Errorf("poll must close in the future")
+	}
+	return nil
+}
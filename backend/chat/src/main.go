@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -11,13 +13,18 @@ import (
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/app"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/client"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/controller"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/gateway"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/repository"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/server"
 	"github.com/PolyTechProjects/chaotic_chat/chat/src/internal/service"
+	"github.com/PolyTechProjects/chaotic_chat/chat/src/redis"
 	_ "github.com/lib/pq"
 )
 
 func main() {
+	reindex := flag.Bool("reindex", false, "rebuild the message search index and exit")
+	flag.Parse()
+
 	cfg := config.MustLoad()
 	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	slog.SetDefault(log)
@@ -26,11 +33,29 @@ func main() {
 	database.Init(cfg)
 	defer database.Close()
 
+	log.Info("Initializing redis connection")
+	redis.Init(cfg)
+	defer redis.Close()
+
 	log.Info("Creating repository")
-	repo := repository.NewChatRepository(database.DB)
+	repo := repository.NewChatRepository(database.DB, redis.RedisClient)
 
 	log.Info("Creating service")
-	service := service.New(*repo)
+	service := service.New(*repo, cfg.App.MessageEditWindow, cfg.App.SearchLanguage, service.MemberLimits{
+		Group:      cfg.App.GroupMemberLimit,
+		Supergroup: cfg.App.SupergroupMemberLimit,
+		Channel:    cfg.App.ChannelMemberLimit,
+	})
+	if *reindex {
+		err := service.ReindexMessages()
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+	go service.RunFileLoadedListener(context.Background())
+	go service.RunRestrictionSweeper(context.Background(), cfg.App.RestrictionSweep)
+	go service.RunPollSweeper(context.Background(), cfg.App.PollSweep)
 
 	slog.Info("Creating auth client")
 	authClient := client.NewAuthClient(cfg)
@@ -42,10 +67,14 @@ func main() {
 	controller := controller.NewChatManagementController(service, authClient, client)
 
 	slog.Info("Creating gRPC server")
-	grpcServer := server.New(service, authClient)
+	grpcServer := server.New(service, authClient, cfg.Grpc.ServiceTokens)
+
+	slog.Info("Creating websocket gateway")
+	gateway := gateway.New(service, authClient, cfg.App.AllowedOrigins)
+	go gateway.Run(context.Background())
 
 	slog.Info("Creating http server")
-	httpServer := server.NewHttpServer(controller)
+	httpServer := server.NewHttpServer(controller, gateway)
 
 	log.Info("Starting application")
 	application := app.New(httpServer, grpcServer, cfg)
@@ -7,6 +7,7 @@ import (
 
 type Config struct {
 	App       AppConfig
+	Grpc      GrpcConfig
 	Auth      AuthConfig
 	Db        DbConfig
 	SeaweedFS SeaweedFSConfig
@@ -15,6 +16,13 @@ type Config struct {
 
 type AppConfig struct {
 	HttpInnerPort int `env:"APP_HTTP_INNER_PORT"`
+	GrpcInnerPort int `env:"APP_GRPC_INNER_PORT"`
+}
+
+type GrpcConfig struct {
+	// Services allowed to call the gRPC API, as name:token pairs separated
+	// by commas.
+	ServiceTokens map[string]string `env:"SERVICE_TOKENS"`
 }
 
 type AuthConfig struct {
@@ -13,12 +13,16 @@ import (
 type App struct {
 	httpServer *server.HttpServer
 	httpPort   int
+	gRPCServer *server.GRPCServer
+	gRPCPort   int
 }
 
-func New(httpServer *server.HttpServer, cfg *config.Config) *App {
+func New(httpServer *server.HttpServer, gRPCServer *server.GRPCServer, cfg *config.Config) *App {
 	return &App{
 		httpServer: httpServer,
 		httpPort:   cfg.App.HttpInnerPort,
+		gRPCServer: gRPCServer,
+		gRPCPort:   cfg.App.GrpcInnerPort,
 	}
 }
 
@@ -29,6 +33,7 @@ func (a *App) MustRun() {
 }
 
 func (a *App) Run() error {
+	go a.RunGRPCServer()
 	a.RunHttpServer()
 	return nil
 }
@@ -46,3 +51,14 @@ func (a *App) RunHttpServer() error {
 	}
 	return nil
 }
+
+func (a *App) RunGRPCServer() error {
+	gl, err := net.Listen("tcp", fmt.Sprintf(":%d", a.gRPCPort))
+	if err != nil {
+		return err
+	}
+	if err = a.gRPCServer.Start(gl); err != nil {
+		return err
+	}
+	return nil
+}
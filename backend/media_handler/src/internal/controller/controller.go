@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/PolyTechProjects/chaotic_chat/media_handler/src/internal/models"
 	"github.com/PolyTechProjects/chaotic_chat/media_handler/src/internal/service"
 	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
 )
 
 type MediaHandlerController struct {
@@ -37,6 +39,15 @@ func (m *MediaHandlerController) UploadMediaHandler(w http.ResponseWriter, r *ht
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	uploaderId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if objectType == "OBJECT_USER" && objectId != uploaderId {
+		http.Error(w, "permission denied", http.StatusForbidden)
+		return
+	}
 	file, fileHeader, err := r.FormFile("file")
 	if err != nil {
 		slog.Error(fmt.Sprintf("r.FormFile returned error: %s", err.Error()))
@@ -44,7 +55,7 @@ func (m *MediaHandlerController) UploadMediaHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	mediaId, err := m.mediaHandlerService.UploadMedia(objectType, objectId, file, fileHeader)
+	mediaId, err := m.mediaHandlerService.UploadMedia(objectType, objectId, uploaderId, file, fileHeader)
 	if err != nil {
 		slog.Error(fmt.Sprintf("m.mediaHandlerService.UploadMedia(%s, %s, file, fileHeader) returned error: %s", objectType, objectIdHeader, err.Error()))
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -118,7 +129,20 @@ func (m *MediaHandlerController) DeleteMediaHandler(w http.ResponseWriter, r *ht
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	err = m.mediaHandlerService.DeleteMedia(fileId)
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = m.mediaHandlerService.DeleteOwnMedia(fileId, userId)
+	if errors.Is(err, service.ErrNotOwner) {
+		http.Error(w, "permission denied", http.StatusForbidden)
+		return
+	}
+	if gorm.IsRecordNotFoundError(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -9,10 +9,19 @@ type Media struct {
 	ObjectType string    `gorm:"not null;check:object_type <> ''"`
 	ObjectId   string    `gorm:"not null;check:object_id <> ''"`
 	FileId     string    `gorm:"not null;check:file_id <> ''"`
+	UploaderId string
 }
 
-func New(id uuid.UUID, objectType string, objectId string, fileId string) *Media {
-	return &Media{ID: id, ObjectType: objectType, ObjectId: objectId, FileId: fileId}
+func New(id uuid.UUID, objectType string, objectId string, fileId string, uploaderId string) *Media {
+	return &Media{ID: id, ObjectType: objectType, ObjectId: objectId, FileId: fileId, UploaderId: uploaderId}
+}
+
+// Avatars uploaded before uploaders were recorded belong to their user.
+func (m *Media) OwnedBy(userId string) bool {
+	if m.UploaderId != "" {
+		return m.UploaderId == userId
+	}
+	return m.ObjectType == "OBJECT_USER" && m.ObjectId == userId
 }
 
 type SeaweedFSAssignResponse struct {
@@ -39,6 +48,7 @@ type UploadMediaRequest struct {
 type MessageIdXFileId struct {
 	MessageId uuid.UUID `json:"messageId"`
 	FileId    uuid.UUID `json:"fileId"`
+	UserId    uuid.UUID `json:"userId"`
 }
 
 type UploadMediaResponse struct {
@@ -1,9 +1,21 @@
 package server
 
 import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net"
 	"net/http"
 
+	"github.com/PolyTechProjects/chaotic_chat/media_handler/src/gen/go/media_handler"
 	"github.com/PolyTechProjects/chaotic_chat/media_handler/src/internal/controller"
+	"github.com/PolyTechProjects/chaotic_chat/media_handler/src/internal/service"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type HttpServer struct {
@@ -19,3 +31,109 @@ func (h *HttpServer) StartServer() {
 	http.HandleFunc("GET /media/uploads", h.mediaHandlerController.GetMediaHandler)
 	http.HandleFunc("DELETE /media/uploads", h.mediaHandlerController.DeleteMediaHandler)
 }
+
+// Other services call with these metadata keys, tokens are configured per
+// service name.
+const (
+	serviceNameKey  = "x-service-name"
+	serviceTokenKey = "x-service-token"
+)
+
+type GRPCServer struct {
+	gRPCServer *grpc.Server
+	media_handler.UnimplementedMediaHandlerServer
+	mediaHandlerService *service.MediaHandlerService
+	serviceTokens       map[string]string
+}
+
+func NewGRPCServer(mediaHandlerService *service.MediaHandlerService, serviceTokens map[string]string) *GRPCServer {
+	gRPCServer := grpc.NewServer()
+	g := &GRPCServer{
+		gRPCServer:          gRPCServer,
+		mediaHandlerService: mediaHandlerService,
+		serviceTokens:       serviceTokens,
+	}
+	media_handler.RegisterMediaHandlerServer(gRPCServer, g)
+	return g
+}
+
+func (s *GRPCServer) Start(l net.Listener) error {
+	slog.Debug("Starting gRPC server")
+	slog.Debug(l.Addr().String())
+	return s.gRPCServer.Serve(l)
+}
+
+func (s *GRPCServer) VerifyMedia(ctx context.Context, req *media_handler.VerifyMediaRequest) (*media_handler.MediaResponse, error) {
+	err := s.authorizeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fileId, err := uuid.Parse(req.FileId)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	media, err := s.mediaHandlerService.GetMediaInfo(fileId)
+	if err != nil {
+		slog.Error(err.Error())
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if media.ObjectType != req.ObjectType {
+		return nil, status.Errorf(codes.FailedPrecondition, "file %s is not of type %s", req.FileId, req.ObjectType)
+	}
+	if media.ObjectId != req.OwnerId {
+		return nil, status.Errorf(codes.PermissionDenied, "file %s does not belong to %s", req.FileId, req.OwnerId)
+	}
+	return &media_handler.MediaResponse{
+		FileId:     media.ID.String(),
+		OwnerId:    media.ObjectId,
+		ObjectType: media.ObjectType,
+	}, nil
+}
+
+func (s *GRPCServer) authorizeService(ctx context.Context) error {
+	names := metadata.ValueFromIncomingContext(ctx, serviceNameKey)
+	tokens := metadata.ValueFromIncomingContext(ctx, serviceTokenKey)
+	if len(names) == 0 || len(tokens) == 0 {
+		return status.Error(codes.Unauthenticated, "service credentials are missing")
+	}
+	expected, ok := s.serviceTokens[names[0]]
+	if !ok || expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(tokens[0])) != 1 {
+		slog.Error("Invalid service credentials", "service", names[0])
+		return status.Error(codes.Unauthenticated, "service credentials are invalid")
+	}
+	return nil
+}
+
+// Only trusted services may delete, and only files of the owner they name.
+func (s *GRPCServer) DeleteMedia(ctx context.Context, req *media_handler.DeleteMediaRequest) (*media_handler.DeleteMediaResponse, error) {
+	err := s.authorizeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fileId, err := uuid.Parse(req.FileId)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	media, err := s.mediaHandlerService.GetMediaInfo(fileId)
+	if err != nil {
+		slog.Error(err.Error())
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !media.OwnedBy(req.OwnerId) {
+		return nil, status.Errorf(codes.PermissionDenied, "file %s does not belong to %s", req.FileId, req.OwnerId)
+	}
+	err = s.mediaHandlerService.DeleteMedia(fileId)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &media_handler.DeleteMediaResponse{}, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -16,6 +17,8 @@ import (
 	"github.com/google/uuid"
 )
 
+var ErrNotOwner = errors.New("file does not belong to the caller")
+
 type MediaHandlerService struct {
 	mediaHandlerRepository *repository.MediaHandlerRepository
 	masterUrl              string
@@ -31,6 +34,7 @@ func New(mediaHandlerRepository *repository.MediaHandlerRepository, cfg *config.
 func (m *MediaHandlerService) UploadMedia(
 	objectType string,
 	objectId uuid.UUID,
+	uploaderId uuid.UUID,
 	file multipart.File,
 	fileHeader *multipart.FileHeader) (uuid.UUID, error) {
 	fileId, err := m.assignFileToSeaweedFS(file, fileHeader.Filename)
@@ -39,7 +43,7 @@ func (m *MediaHandlerService) UploadMedia(
 		return uuid.Nil, err
 	}
 	id := uuid.New()
-	media := models.New(id, objectType, objectId.String(), fileId)
+	media := models.New(id, objectType, objectId.String(), fileId, uploaderId.String())
 	err = m.mediaHandlerRepository.Save(media)
 	if err != nil {
 		slog.Error(err.Error())
@@ -56,6 +60,7 @@ func (m *MediaHandlerService) UploadMedia(
 		mf := models.MessageIdXFileId{
 			MessageId: messageId,
 			FileId:    media.ID,
+			UserId:    uploaderId,
 		}
 		bytes, err := json.Marshal(mf)
 		if err != nil {
@@ -94,6 +99,21 @@ func (m *MediaHandlerService) GetMedia(id uuid.UUID) ([]byte, error) {
 	return b, nil
 }
 
+func (m *MediaHandlerService) GetMediaInfo(id uuid.UUID) (*models.Media, error) {
+	return m.mediaHandlerRepository.FindById(id)
+}
+
+func (m *MediaHandlerService) DeleteOwnMedia(id uuid.UUID, userId uuid.UUID) error {
+	media, err := m.mediaHandlerRepository.FindById(id)
+	if err != nil {
+		return err
+	}
+	if !media.OwnedBy(userId.String()) {
+		return ErrNotOwner
+	}
+	return m.DeleteMedia(id)
+}
+
 func (m *MediaHandlerService) DeleteMedia(id uuid.UUID) error {
 	fileId, volumeAddress, err := m.lookUpForFileIdAndVolumeAddress(id)
 	if err != nil {
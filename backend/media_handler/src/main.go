@@ -30,7 +30,8 @@ func main() {
 	service := service.New(repository, cfg)
 	controller := controller.New(service, authClient)
 	httpServer := server.NewHttpServer(controller)
-	app := app.New(httpServer, cfg)
+	grpcServer := server.NewGRPCServer(service, cfg.Grpc.ServiceTokens)
+	app := app.New(httpServer, grpcServer, cfg)
 	go app.MustRun()
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 )
@@ -9,12 +11,14 @@ type Config struct {
 	App   AppConfig
 	Auth  AuthConfig
 	Media MediaHandlerConfig
+	Chat  ChatConfig
 	Db    DbConfig
 }
 
 type AppConfig struct {
-	HttpInnerPort int `env:"APP_HTTP_INNER_PORT"`
-	GrpcInnerPort int `env:"APP_GRPC_INNER_PORT"`
+	HttpInnerPort       int           `env:"APP_HTTP_INNER_PORT"`
+	GrpcInnerPort       int           `env:"APP_GRPC_INNER_PORT"`
+	StatusSweepInterval time.Duration `env:"STATUS_SWEEP_INTERVAL" env-default:"1m"`
 }
 
 type AuthConfig struct {
@@ -25,6 +29,14 @@ type AuthConfig struct {
 type MediaHandlerConfig struct {
 	MediaHandlerHost string `env:"MEDIA_HANDLER_HOST"`
 	MediaHandlerPort string `env:"MEDIA_HANDLER_PORT"`
+	// Sent as user_mgmt's service token, media_handler only lets trusted
+	// services delete files.
+	ServiceToken string `env:"MEDIA_HANDLER_SERVICE_TOKEN"`
+}
+
+type ChatConfig struct {
+	ChatHost string `env:"CHAT_HOST"`
+	ChatPort string `env:"CHAT_PORT"`
 }
 
 type DbConfig struct {
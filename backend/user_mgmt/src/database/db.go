@@ -36,7 +36,7 @@ func Init(cfg *config.Config) {
 		log.Panicln(err, str)
 		panic(err.Error())
 	}
-	db.AutoMigrate(&models.User{})
+	db.AutoMigrate(&models.User{}, &models.Avatar{}, &models.Settings{}, &models.ChatNotificationSettings{}, &models.Report{}, &models.ModerationAction{})
 	DB = db
 	slog.Debug("Connected to DB")
 }
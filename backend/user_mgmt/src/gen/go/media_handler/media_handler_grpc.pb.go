@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.12.4
+// source: media_handler/media_handler.proto
+
+package media_handler
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MediaHandlerClient is the client API for MediaHandler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MediaHandlerClient interface {
+	VerifyMedia(ctx context.Context, in *VerifyMediaRequest, opts ...grpc.CallOption) (*MediaResponse, error)
+	DeleteMedia(ctx context.Context, in *DeleteMediaRequest, opts ...grpc.CallOption) (*DeleteMediaResponse, error)
+}
+
+type mediaHandlerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMediaHandlerClient(cc grpc.ClientConnInterface) MediaHandlerClient {
+	return &mediaHandlerClient{cc}
+}
+
+func (c *mediaHandlerClient) VerifyMedia(ctx context.Context, in *VerifyMediaRequest, opts ...grpc.CallOption) (*MediaResponse, error) {
+	out := new(MediaResponse)
+	err := c.cc.Invoke(ctx, "/media_handler.MediaHandler/VerifyMedia", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaHandlerClient) DeleteMedia(ctx context.Context, in *DeleteMediaRequest, opts ...grpc.CallOption) (*DeleteMediaResponse, error) {
+	out := new(DeleteMediaResponse)
+	err := c.cc.Invoke(ctx, "/media_handler.MediaHandler/DeleteMedia", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MediaHandlerServer is the server API for MediaHandler service.
+// All implementations must embed UnimplementedMediaHandlerServer
+// for forward compatibility
+type MediaHandlerServer interface {
+	VerifyMedia(context.Context, *VerifyMediaRequest) (*MediaResponse, error)
+	DeleteMedia(context.Context, *DeleteMediaRequest) (*DeleteMediaResponse, error)
+	mustEmbedUnimplementedMediaHandlerServer()
+}
+
+// UnimplementedMediaHandlerServer must be embedded to have forward compatible implementations.
+type UnimplementedMediaHandlerServer struct {
+}
+
+func (UnimplementedMediaHandlerServer) VerifyMedia(context.Context, *VerifyMediaRequest) (*MediaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMedia not implemented")
+}
+func (UnimplementedMediaHandlerServer) DeleteMedia(context.Context, *DeleteMediaRequest) (*DeleteMediaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMedia not implemented")
+}
+func (UnimplementedMediaHandlerServer) mustEmbedUnimplementedMediaHandlerServer() {}
+
+// UnsafeMediaHandlerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MediaHandlerServer will
+// result in compilation errors.
+type UnsafeMediaHandlerServer interface {
+	mustEmbedUnimplementedMediaHandlerServer()
+}
+
+func RegisterMediaHandlerServer(s grpc.ServiceRegistrar, srv MediaHandlerServer) {
+	s.RegisterService(&MediaHandler_ServiceDesc, srv)
+}
+
+func _MediaHandler_VerifyMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaHandlerServer).VerifyMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/media_handler.MediaHandler/VerifyMedia",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaHandlerServer).VerifyMedia(ctx, req.(*VerifyMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaHandler_DeleteMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaHandlerServer).DeleteMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/media_handler.MediaHandler/DeleteMedia",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaHandlerServer).DeleteMedia(ctx, req.(*DeleteMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MediaHandler_ServiceDesc is the grpc.ServiceDesc for MediaHandler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MediaHandler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "media_handler.MediaHandler",
+	HandlerType: (*MediaHandlerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "VerifyMedia",
+			Handler:    _MediaHandler_VerifyMedia_Handler,
+		},
+		{
+			MethodName: "DeleteMedia",
+			Handler:    _MediaHandler_DeleteMedia_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "media_handler/media_handler.proto",
+}
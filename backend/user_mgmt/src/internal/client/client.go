@@ -9,9 +9,13 @@ import (
 
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/config"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/gen/go/auth"
+	chat_mgmt "github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/gen/go/chat"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/gen/go/media_handler"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type AuthGRPCClient struct {
@@ -32,7 +36,11 @@ func NewAuthClient(cfg *config.Config) *AuthGRPCClient {
 func (authClient *AuthGRPCClient) PerformAuthorize(ctx context.Context, r *http.Request) (*auth.AuthorizeResponse, error) {
 	var accessToken string
 	if r == nil {
-		accessToken = metadata.ValueFromIncomingContext(ctx, "authorization")[0]
+		values := metadata.ValueFromIncomingContext(ctx, "authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "authorization is missing")
+		}
+		accessToken = values[0]
 	} else {
 		ctx = r.Context()
 		authHeader := r.Header.Get("Authorization")
@@ -40,3 +48,67 @@ func (authClient *AuthGRPCClient) PerformAuthorize(ctx context.Context, r *http.
 	}
 	return authClient.Authorize(ctx, &auth.AuthorizeRequest{AccessToken: accessToken})
 }
+
+func (authClient *AuthGRPCClient) PerformSetSuspension(ctx context.Context, userId string, suspendedUntil int64) (*auth.SetSuspensionResponse, error) {
+	return authClient.SetSuspension(ctx, &auth.SetSuspensionRequest{UserId: userId, SuspendedUntil: suspendedUntil})
+}
+
+func WithAuthorization(ctx context.Context, accessToken string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", accessToken)
+}
+
+type MediaHandlerGRPCClient struct {
+	media_handler.MediaHandlerClient
+	serviceToken string
+}
+
+func NewMediaHandlerClient(cfg *config.Config) *MediaHandlerGRPCClient {
+	connectionUrl := fmt.Sprintf("%s:%s", cfg.Media.MediaHandlerHost, cfg.Media.MediaHandlerPort)
+	conn, err := grpc.NewClient(connectionUrl, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic("failed to connect: " + err.Error())
+	}
+	slog.Info("Connected to Media Handler")
+	slog.Info(connectionUrl)
+	return &MediaHandlerGRPCClient{media_handler.NewMediaHandlerClient(conn), cfg.Media.ServiceToken}
+}
+
+func (c *MediaHandlerGRPCClient) withServiceCredentials(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-service-name", "user_mgmt", "x-service-token", c.serviceToken)
+}
+
+func (c *MediaHandlerGRPCClient) PerformVerifyAvatar(ctx context.Context, fileId string, ownerId string) (*media_handler.MediaResponse, error) {
+	ctx = c.withServiceCredentials(ctx)
+	return c.VerifyMedia(ctx, &media_handler.VerifyMediaRequest{FileId: fileId, OwnerId: ownerId, ObjectType: "OBJECT_USER"})
+}
+
+func (c *MediaHandlerGRPCClient) PerformDeleteMedia(ctx context.Context, fileId string, ownerId string) error {
+	ctx = c.withServiceCredentials(ctx)
+	_, err := c.DeleteMedia(ctx, &media_handler.DeleteMediaRequest{FileId: fileId, OwnerId: ownerId})
+	return err
+}
+
+type ChatGRPCClient struct {
+	chat_mgmt.ChatManagementClient
+}
+
+func NewChatClient(cfg *config.Config) *ChatGRPCClient {
+	connectionUrl := fmt.Sprintf("%s:%s", cfg.Chat.ChatHost, cfg.Chat.ChatPort)
+	conn, err := grpc.NewClient(connectionUrl, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic("failed to connect: " + err.Error())
+	}
+	slog.Info("Connected to Chat")
+	slog.Info(connectionUrl)
+	return &ChatGRPCClient{chat_mgmt.NewChatManagementClient(conn)}
+}
+
+func (c *ChatGRPCClient) PerformDeleteChat(ctx context.Context, chatId string) error {
+	_, err := c.DeleteChat(ctx, &chat_mgmt.DeleteChatRequest{ChatId: chatId})
+	return err
+}
+
+func (c *ChatGRPCClient) PerformLeaveAllChats(ctx context.Context, userId string) error {
+	_, err := c.LeaveAllChats(ctx, &chat_mgmt.LeaveAllChatsRequest{UserId: userId})
+	return err
+}
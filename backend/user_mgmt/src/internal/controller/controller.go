@@ -2,14 +2,17 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/gen/go/auth"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/client"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/dto"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/service"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/validator"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
 	"github.com/google/uuid"
 )
 
@@ -29,19 +32,132 @@ func (c *UserMgmtController) UpdateAvatarHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Uploads belong to the uploader, so only users can set their own avatar.
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var req dto.UploadProfilePicRequest
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	userId, err := uuid.Parse(req.UserId)
+
+	user, err := c.userMgmtService.UpdateAvatar(r.Context(), userId, req.FileId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := json.Marshal(user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(resp)
+}
+
+func (c *UserMgmtController) GetAvatarsHandler(w http.ResponseWriter, r *http.Request) {
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	avatars, err := c.userMgmtService.GetAvatars(userId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := json.Marshal(avatars)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(resp)
+}
+
+func (c *UserMgmtController) SetAvatarHandler(w http.ResponseWriter, r *http.Request) {
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.SetAvatarRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	avatarId, err := uuid.Parse(req.AvatarId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := c.userMgmtService.SetCurrentAvatar(userId, avatarId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := json.Marshal(user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(resp)
+}
+
+func (c *UserMgmtController) DeleteAvatarHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !params.Has("avatarId") {
+		http.Error(w, "URL query params are invalid", http.StatusBadRequest)
+		return
+	}
+	avatarId, err := uuid.Parse(params.Get("avatarId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	user, err := c.userMgmtService.UpdateAvatar(userId, req.FileId)
+	user, err := c.userMgmtService.DeleteAvatar(r.Context(), userId, avatarId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -71,9 +187,9 @@ func (c *UserMgmtController) InfoUpdateHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	userId, err := uuid.Parse(dto.UserId)
+	userId, code, err := targetUserId(r, authResp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), code)
 		return
 	}
 
@@ -92,8 +208,76 @@ func (c *UserMgmtController) InfoUpdateHandler(w http.ResponseWriter, r *http.Re
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	err = validator.ValidateBirthday(dto.Birthday)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidatePronouns(dto.Pronouns)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateLinks(dto.Links)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateTimezone(dto.Timezone)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := c.userMgmtService.UpdateUser(userId, &dto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := json.Marshal(user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(resp)
+}
+
+func (c *UserMgmtController) UpdateStatusHandler(w http.ResponseWriter, r *http.Request) {
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UpdateStatusRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateStatusEmoji(req.StatusEmoji)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateStatusText(req.StatusText)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateStatusExpiresAt(req.ExpiresAt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	user, err := c.userMgmtService.UpdateUser(userId, dto.Name, dto.UrlTag, dto.Description)
+	user, err := c.userMgmtService.UpdateStatus(userId, &req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -103,6 +287,8 @@ func (c *UserMgmtController) InfoUpdateHandler(w http.ResponseWriter, r *http.Re
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
 	w.Write(resp)
 }
@@ -142,29 +328,71 @@ func (c *UserMgmtController) GetUserHandler(w http.ResponseWriter, r *http.Reque
 	w.Write(resp)
 }
 
-func (c *UserMgmtController) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
-	dto := dto.UpdateInfoRequest{}
-	err := json.NewDecoder(r.Body).Decode(&dto)
+func (c *UserMgmtController) GetMeHandler(w http.ResponseWriter, r *http.Request) {
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	userId, err := uuid.Parse(authResp.UserId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	user, err := c.userMgmtService.GetUser(userId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	resp, err := json.Marshal(user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(resp)
+}
+
+func (c *UserMgmtController) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
 	}
 
-	userId, err := uuid.Parse(dto.UserId)
+	userId, code, err := targetUserId(r, authResp)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), code)
 		return
 	}
-	err = c.userMgmtService.DeleteUser(userId)
+	ctx := client.WithAuthorization(r.Context(), authResp.AccessToken)
+	err = c.userMgmtService.DeleteUser(ctx, userId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
 }
+
+// /user/me routes act on the caller; the /user/{userId} override is admin-only.
+func targetUserId(r *http.Request, authResp *auth.AuthorizeResponse) (uuid.UUID, int, error) {
+	pathUserId := r.PathValue("userId")
+	if pathUserId == "" {
+		userId, err := uuid.Parse(authResp.UserId)
+		if err != nil {
+			return uuid.Nil, http.StatusBadRequest, err
+		}
+		return userId, http.StatusOK, nil
+	}
+	if authResp.Role != models.RoleAdmin {
+		return uuid.Nil, http.StatusForbidden, errors.New("permission denied")
+	}
+	userId, err := uuid.Parse(pathUserId)
+	if err != nil {
+		return uuid.Nil, http.StatusBadRequest, err
+	}
+	return userId, http.StatusOK, nil
+}
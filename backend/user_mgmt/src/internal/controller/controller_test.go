@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/gen/go/auth"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/client"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+type fakeAuthClient struct {
+	auth.AuthClient
+	resp *auth.AuthorizeResponse
+}
+
+func (f *fakeAuthClient) Authorize(ctx context.Context, in *auth.AuthorizeRequest, opts ...grpc.CallOption) (*auth.AuthorizeResponse, error) {
+	return f.resp, nil
+}
+
+func newTestController(userId uuid.UUID, role string) *UserMgmtController {
+	authClient := &client.AuthGRPCClient{AuthClient: &fakeAuthClient{
+		resp: &auth.AuthorizeResponse{AccessToken: "token", UserId: userId.String(), Role: role},
+	}}
+	return New(nil, authClient)
+}
+
+func TestCrossUserMutationsAreRejected(t *testing.T) {
+	caller := uuid.New()
+	victim := uuid.New()
+	c := newTestController(caller, "user")
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		handler http.HandlerFunc
+	}{
+		{"update info", http.MethodPut, "/user/" + victim.String(), `{"name":"Mallory","url_tag":"mallory"}`, c.InfoUpdateHandler},
+		{"delete user", http.MethodDelete, "/user/" + victim.String(), ``, c.DeleteUserHandler},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			r.Header.Set("Authorization", "Bearer token")
+			r.SetPathValue("userId", victim.String())
+			w := httptest.NewRecorder()
+
+			tt.handler(w, r)
+
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("expected status %d, got %d (%s)", http.StatusForbidden, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTargetUserId(t *testing.T) {
+	caller := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name       string
+		role       string
+		pathUserId string
+		wantUserId uuid.UUID
+		wantCode   int
+	}{
+		{"self route uses caller", "user", "", caller, http.StatusOK},
+		{"self route ignores admin role", models.RoleAdmin, "", caller, http.StatusOK},
+		{"user cannot target another user", "user", other.String(), uuid.Nil, http.StatusForbidden},
+		{"user cannot target self via override", "user", caller.String(), uuid.Nil, http.StatusForbidden},
+		{"admin can target another user", models.RoleAdmin, other.String(), other, http.StatusOK},
+		{"admin override needs a valid id", models.RoleAdmin, "not-a-uuid", uuid.Nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/user/me", nil)
+			if tt.pathUserId != "" {
+				r.SetPathValue("userId", tt.pathUserId)
+			}
+			authResp := &auth.AuthorizeResponse{UserId: caller.String(), Role: tt.role}
+
+			userId, code, err := targetUserId(r, authResp)
+
+			if code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d (err: %v)", tt.wantCode, code, err)
+			}
+			if userId != tt.wantUserId {
+				t.Fatalf("expected user %v, got %v", tt.wantUserId, userId)
+			}
+			if (err != nil) != (tt.wantCode != http.StatusOK) {
+				t.Fatalf("unexpected error value: %v", err)
+			}
+		})
+	}
+}
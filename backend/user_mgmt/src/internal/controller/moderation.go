@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/gen/go/auth"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/client"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/service"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/validator"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+func (c *UserMgmtController) CreateReportHandler(w http.ResponseWriter, r *http.Request) {
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	reporterId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.CreateReportRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateReportTargetType(req.TargetType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateReportReason(req.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateReportDetails(req.Details)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := c.userMgmtService.CreateReport(reporterId, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeModerationJSON(w, authResp, report)
+}
+
+func (c *UserMgmtController) GetReportsHandler(w http.ResponseWriter, r *http.Request) {
+	authResp, ok := c.authorizeModerator(w, r)
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	err := validator.ValidateReportStatus(status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reports, err := c.userMgmtService.GetReports(status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeModerationJSON(w, authResp, reports)
+}
+
+func (c *UserMgmtController) ApplyModerationActionHandler(w http.ResponseWriter, r *http.Request) {
+	authResp, ok := c.authorizeModerator(w, r)
+	if !ok {
+		return
+	}
+	moderatorId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ModerationActionRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validator.ValidateModerationAction(req.Action)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := client.WithAuthorization(r.Context(), authResp.AccessToken)
+	action, err := c.userMgmtService.ApplyModerationAction(ctx, moderatorId, &req)
+	if err != nil {
+		code := http.StatusBadRequest
+		if gorm.IsRecordNotFoundError(err) {
+			code = http.StatusNotFound
+		} else if errors.Is(err, service.ErrReportNotOpen) {
+			code = http.StatusConflict
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	writeModerationJSON(w, authResp, action)
+}
+
+func (c *UserMgmtController) GetModerationActionsHandler(w http.ResponseWriter, r *http.Request) {
+	authResp, ok := c.authorizeModerator(w, r)
+	if !ok {
+		return
+	}
+
+	var targetId *uuid.UUID
+	if rawTargetId := r.URL.Query().Get("targetId"); rawTargetId != "" {
+		parsed, err := uuid.Parse(rawTargetId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		targetId = &parsed
+	}
+	actions, err := c.userMgmtService.GetModerationActions(targetId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeModerationJSON(w, authResp, actions)
+}
+
+func (c *UserMgmtController) authorizeModerator(w http.ResponseWriter, r *http.Request) (*auth.AuthorizeResponse, bool) {
+	authResp, err := c.authClient.PerformAuthorize(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+	if !models.CanModerate(authResp.Role) {
+		http.Error(w, "permission denied", http.StatusForbidden)
+		return nil, false
+	}
+	return authResp, true
+}
+
+func writeModerationJSON(w http.ResponseWriter, authResp *auth.AuthorizeResponse, v any) {
+	resp, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Set-Cookie", fmt.Sprintf("Authorization=%s; HttpOnly", authResp.AccessToken))
+	w.Write(resp)
+}
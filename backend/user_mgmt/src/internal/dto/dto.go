@@ -1,10 +1,25 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
+)
+
 type UpdateInfoRequest struct {
-	UserId      string `json:"user_id"`
-	Name        string `json:"name"`
-	UrlTag      string `json:"url_tag"`
-	Description string `json:"description"`
+	Name        string   `json:"name"`
+	UrlTag      string   `json:"url_tag"`
+	Description string   `json:"description"`
+	Birthday    string   `json:"birthday"`
+	Pronouns    string   `json:"pronouns"`
+	Links       []string `json:"links"`
+	Timezone    string   `json:"timezone"`
+}
+
+type UpdateStatusRequest struct {
+	StatusEmoji string     `json:"status_emoji"`
+	StatusText  string     `json:"status_text"`
+	ExpiresAt   *time.Time `json:"expires_at"`
 }
 
 type UpdateInfoResponse struct {
@@ -12,10 +27,13 @@ type UpdateInfoResponse struct {
 }
 
 type UploadProfilePicRequest struct {
-	UserId string `json:"user_id"`
 	FileId string `json:"file_id"`
 }
 
+type SetAvatarRequest struct {
+	AvatarId string `json:"avatar_id"`
+}
+
 type GetUserResponse struct {
 	UserId      string `json:"user_id"`
 	Name        string `json:"name"`
@@ -24,6 +42,53 @@ type GetUserResponse struct {
 	Avatar      string `json:"avatar"`
 }
 
-type DeleteUserRequest struct {
-	UserId string `json:"user_id"`
+type UpdateSettingsRequest struct {
+	Version              int64  `json:"version"`
+	DeviceId             string `json:"device_id"`
+	Language             string `json:"language"`
+	Theme                string `json:"theme"`
+	NotificationsEnabled bool   `json:"notifications_enabled"`
+	NotificationSound    bool   `json:"notification_sound"`
+	NotificationPreview  bool   `json:"notification_preview"`
+	AutoDownloadPhotos   string `json:"auto_download_photos"`
+	AutoDownloadVideos   string `json:"auto_download_videos"`
+	AutoDownloadFiles    string `json:"auto_download_files"`
+	AutoDownloadMaxSize  int64  `json:"auto_download_max_size"`
+}
+
+type UpdateChatNotificationSettingsRequest struct {
+	Version     int64      `json:"version"`
+	DeviceId    string     `json:"device_id"`
+	MuteUntil   *time.Time `json:"mute_until"`
+	Sound       bool       `json:"sound"`
+	ShowPreview bool       `json:"show_preview"`
+}
+
+type SettingsResponse struct {
+	Settings          *models.Settings `json:"settings"`
+	Conflict          bool             `json:"conflict"`
+	ConflictingDevice string           `json:"conflicting_device,omitempty"`
+	Overwritten       *models.Settings `json:"overwritten,omitempty"`
+}
+
+type ChatNotificationSettingsResponse struct {
+	Settings          *models.ChatNotificationSettings `json:"settings"`
+	Conflict          bool                             `json:"conflict"`
+	ConflictingDevice string                           `json:"conflicting_device,omitempty"`
+	Overwritten       *models.ChatNotificationSettings `json:"overwritten,omitempty"`
+}
+
+type CreateReportRequest struct {
+	TargetType string `json:"target_type"`
+	TargetId   string `json:"target_id"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details"`
+}
+
+type ModerationActionRequest struct {
+	Action         string     `json:"action"`
+	TargetId       string     `json:"target_id"`
+	ReportId       string     `json:"report_id"`
+	Reason         string     `json:"reason"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
 }
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
+	"github.com/google/uuid"
+)
+
+func (r *UserMgmtRepository) InsertReport(report *models.Report) error {
+	return r.db.Create(report).Error
+}
+
+func (r *UserMgmtRepository) GetReportForUpdate(reportId uuid.UUID) (*models.Report, error) {
+	var report models.Report
+	err := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", reportId).First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *UserMgmtRepository) GetReports(status string) ([]models.Report, error) {
+	var reports []models.Report
+	query := r.db.Order("created_at asc")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&reports).Error
+	return reports, err
+}
+
+func (r *UserMgmtRepository) UpdateReport(report *models.Report) error {
+	return r.db.Save(report).Error
+}
+
+func (r *UserMgmtRepository) InsertModerationAction(action *models.ModerationAction) error {
+	return r.db.Create(action).Error
+}
+
+func (r *UserMgmtRepository) UpdateModerationActionStatus(actionId uuid.UUID, status string) error {
+	return r.db.Model(&models.ModerationAction{}).Where("id = ?", actionId).UpdateColumn("status", status).Error
+}
+
+func (r *UserMgmtRepository) GetModerationActions(targetId *uuid.UUID) ([]models.ModerationAction, error) {
+	var actions []models.ModerationAction
+	query := r.db.Order("created_at desc")
+	if targetId != nil {
+		query = query.Where("target_id = ?", *targetId)
+	}
+	err := query.Find(&actions).Error
+	return actions, err
+}
+
+func (r *UserMgmtRepository) GetAvatarByFileId(userId uuid.UUID, fileId string) (*models.Avatar, error) {
+	var avatar models.Avatar
+	err := r.db.Where("user_id = ? AND file_id = ?", userId, fileId).First(&avatar).Error
+	if err != nil {
+		return nil, err
+	}
+	return &avatar, nil
+}
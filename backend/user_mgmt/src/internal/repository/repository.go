@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
@@ -44,3 +47,62 @@ func (r *UserMgmtRepository) DeleteUser(userId uuid.UUID) error {
 	var user models.User
 	return r.db.Where("id = ?", userId).Delete(user).Error
 }
+
+func (r *UserMgmtRepository) GetAllUsers() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Find(&users).Error
+	return users, err
+}
+
+func (r *UserMgmtRepository) SearchUsers(query string, userIds []uuid.UUID, limit int) ([]models.User, error) {
+	var users []models.User
+	if len(userIds) == 0 {
+		return users, nil
+	}
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+	err := r.db.
+		Where("id IN (?) AND (name ILIKE ? OR url_tag ILIKE ?)", userIds, pattern, pattern).
+		Order("id").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *UserMgmtRepository) GetUsersByIds(userIds []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if len(userIds) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN (?)", userIds).Find(&users).Error
+	return users, err
+}
+
+func (r *UserMgmtRepository) ClearExpiredStatuses(now time.Time) (int64, error) {
+	res := r.db.Model(&models.User{}).
+		Where("status_expires_at IS NOT NULL AND status_expires_at <= ?", now).
+		Updates(map[string]interface{}{"status_emoji": "", "status_text": "", "status_expires_at": nil})
+	return res.RowsAffected, res.Error
+}
+
+func (r *UserMgmtRepository) InsertAvatar(avatar *models.Avatar) error {
+	return r.db.Create(avatar).Error
+}
+
+func (r *UserMgmtRepository) GetAvatar(avatarId uuid.UUID) (*models.Avatar, error) {
+	var avatar models.Avatar
+	err := r.db.Where("id = ?", avatarId).First(&avatar).Error
+	if err != nil {
+		return nil, err
+	}
+	return &avatar, nil
+}
+
+func (r *UserMgmtRepository) GetAvatars(userId uuid.UUID) ([]models.Avatar, error) {
+	var avatars []models.Avatar
+	err := r.db.Where("user_id = ?", userId).Order("created_at desc").Find(&avatars).Error
+	return avatars, err
+}
+
+func (r *UserMgmtRepository) DeleteAvatar(avatarId uuid.UUID) error {
+	return r.db.Where("id = ?", avatarId).Delete(&models.Avatar{}).Error
+}
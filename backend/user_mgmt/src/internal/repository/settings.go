@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
+	"github.com/google/uuid"
+)
+
+func (r *UserMgmtRepository) Transaction(fn func(tx *UserMgmtRepository) error) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if err := fn(&UserMgmtRepository{db: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (r *UserMgmtRepository) GetSettings(userId uuid.UUID) (*models.Settings, error) {
+	var settings models.Settings
+	err := r.db.Where("user_id = ?", userId).First(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *UserMgmtRepository) GetSettingsForUpdate(userId uuid.UUID) (*models.Settings, error) {
+	var settings models.Settings
+	err := r.db.Set("gorm:query_option", "FOR UPDATE").Where("user_id = ?", userId).First(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// Every column is written, gorm would leave false booleans to the column
+// default on insert.
+func (r *UserMgmtRepository) SaveSettings(settings *models.Settings) error {
+	return r.db.Exec(
+		`INSERT INTO settings (user_id, language, theme, notifications_enabled, notification_sound, notification_preview,
+			auto_download_photos, auto_download_videos, auto_download_files, auto_download_max_size, version, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET language = EXCLUDED.language, theme = EXCLUDED.theme,
+			notifications_enabled = EXCLUDED.notifications_enabled, notification_sound = EXCLUDED.notification_sound,
+			notification_preview = EXCLUDED.notification_preview, auto_download_photos = EXCLUDED.auto_download_photos,
+			auto_download_videos = EXCLUDED.auto_download_videos, auto_download_files = EXCLUDED.auto_download_files,
+			auto_download_max_size = EXCLUDED.auto_download_max_size, version = EXCLUDED.version,
+			updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`,
+		settings.UserId, settings.Language, settings.Theme, settings.NotificationsEnabled, settings.NotificationSound, settings.NotificationPreview,
+		settings.AutoDownloadPhotos, settings.AutoDownloadVideos, settings.AutoDownloadFiles, settings.AutoDownloadMaxSize, settings.Version, settings.UpdatedBy, settings.UpdatedAt,
+	).Error
+}
+
+func (r *UserMgmtRepository) GetChatNotificationSettings(userId uuid.UUID, chatIds []uuid.UUID) ([]models.ChatNotificationSettings, error) {
+	var settings []models.ChatNotificationSettings
+	err := r.db.Where("user_id = ? AND chat_id IN (?)", userId, chatIds).Find(&settings).Error
+	return settings, err
+}
+
+func (r *UserMgmtRepository) GetChatNotificationSettingsForUpdate(userId uuid.UUID, chatId uuid.UUID) (*models.ChatNotificationSettings, error) {
+	var settings models.ChatNotificationSettings
+	err := r.db.Set("gorm:query_option", "FOR UPDATE").Where("user_id = ? AND chat_id = ?", userId, chatId).First(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *UserMgmtRepository) SaveChatNotificationSettings(settings *models.ChatNotificationSettings) error {
+	return r.db.Exec(
+		`INSERT INTO chat_notification_settings (user_id, chat_id, mute_until, sound, show_preview, version, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, chat_id) DO UPDATE SET mute_until = EXCLUDED.mute_until, sound = EXCLUDED.sound,
+			show_preview = EXCLUDED.show_preview, version = EXCLUDED.version,
+			updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`,
+		settings.UserId, settings.ChatId, settings.MuteUntil, settings.Sound, settings.ShowPreview, settings.Version, settings.UpdatedBy, settings.UpdatedAt,
+	).Error
+}
@@ -2,16 +2,24 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/gen/go/auth"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/gen/go/user_mgmt"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/client"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/controller"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/dto"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/service"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/validator"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
 	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -26,10 +34,25 @@ func NewHttpServer(userMgmtController *controller.UserMgmtController) *HttpServe
 }
 
 func (h *HttpServer) StartServer() {
-	http.HandleFunc("POST /user/profile/pic", h.userMgmtController.UpdateAvatarHandler)
-	http.HandleFunc("PUT /user/profile", h.userMgmtController.InfoUpdateHandler)
 	http.HandleFunc("GET /user/profile", h.userMgmtController.GetUserHandler)
-	http.HandleFunc("DELETE /user/profile", h.userMgmtController.DeleteUserHandler)
+	http.HandleFunc("GET /user/me", h.userMgmtController.GetMeHandler)
+	http.HandleFunc("PUT /user/me", h.userMgmtController.InfoUpdateHandler)
+	http.HandleFunc("DELETE /user/me", h.userMgmtController.DeleteUserHandler)
+	http.HandleFunc("PUT /user/me/status", h.userMgmtController.UpdateStatusHandler)
+	http.HandleFunc("POST /user/me/pic", h.userMgmtController.UpdateAvatarHandler)
+	http.HandleFunc("PUT /user/me/pic", h.userMgmtController.SetAvatarHandler)
+	http.HandleFunc("DELETE /user/me/pic", h.userMgmtController.DeleteAvatarHandler)
+	http.HandleFunc("GET /user/me/pics", h.userMgmtController.GetAvatarsHandler)
+	http.HandleFunc("GET /user/me/settings", h.userMgmtController.GetSettingsHandler)
+	http.HandleFunc("PUT /user/me/settings", h.userMgmtController.UpdateSettingsHandler)
+	http.HandleFunc("GET /user/me/settings/chats/{chatId}", h.userMgmtController.GetChatNotificationSettingsHandler)
+	http.HandleFunc("PUT /user/me/settings/chats/{chatId}", h.userMgmtController.UpdateChatNotificationSettingsHandler)
+	http.HandleFunc("PUT /user/{userId}", h.userMgmtController.InfoUpdateHandler)
+	http.HandleFunc("DELETE /user/{userId}", h.userMgmtController.DeleteUserHandler)
+	http.HandleFunc("POST /user/reports", h.userMgmtController.CreateReportHandler)
+	http.HandleFunc("GET /moderation/reports", h.userMgmtController.GetReportsHandler)
+	http.HandleFunc("POST /moderation/actions", h.userMgmtController.ApplyModerationActionHandler)
+	http.HandleFunc("GET /moderation/actions", h.userMgmtController.GetModerationActionsHandler)
 }
 
 type UserMgmtGRPCServer struct {
@@ -68,12 +91,254 @@ func (s *UserMgmtGRPCServer) AddUser(ctx context.Context, req *user_mgmt.AddUser
 		slog.Error(err.Error())
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	return toUserResponse(user), nil
+}
+
+func (s *UserMgmtGRPCServer) GetAllUsers(ctx context.Context, req *user_mgmt.GetAllUsersRequest) (*user_mgmt.GetAllUsersResponse, error) {
+	users, err := s.userMgmtService.GetAllUsers()
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &user_mgmt.GetAllUsersResponse{Users: make([]*user_mgmt.UserResponse, 0, len(users))}
+	for i := range users {
+		resp.Users = append(resp.Users, toUserResponse(&users[i]))
+	}
+	return resp, nil
+}
+
+const maxUsersByIds = 1000
+
+func (s *UserMgmtGRPCServer) GetUsersByIds(ctx context.Context, req *user_mgmt.GetUsersByIdsRequest) (*user_mgmt.GetAllUsersResponse, error) {
+	userIds, err := parseUserIds(req.UserIds)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userMgmtService.GetUsersByIds(userIds)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &user_mgmt.GetAllUsersResponse{Users: make([]*user_mgmt.UserResponse, 0, len(users))}
+	for i := range users {
+		resp.Users = append(resp.Users, toUserResponse(&users[i]))
+	}
+	return resp, nil
+}
+
+func (s *UserMgmtGRPCServer) SearchUsers(ctx context.Context, req *user_mgmt.SearchUsersRequest) (*user_mgmt.GetAllUsersResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is blank")
+	}
+	userIds, err := parseUserIds(req.UserIds)
+	if err != nil {
+		return nil, err
+	}
+	limit := int(req.Limit)
+	if limit <= 0 || limit > maxUsersByIds {
+		limit = maxUsersByIds
+	}
+	users, err := s.userMgmtService.SearchUsers(query, userIds, limit)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &user_mgmt.GetAllUsersResponse{Users: make([]*user_mgmt.UserResponse, 0, len(users))}
+	for i := range users {
+		resp.Users = append(resp.Users, toUserResponse(&users[i]))
+	}
+	return resp, nil
+}
+
+func parseUserIds(rawIds []string) ([]uuid.UUID, error) {
+	if len(rawIds) > maxUsersByIds {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d user ids are allowed", maxUsersByIds))
+	}
+	userIds := make([]uuid.UUID, 0, len(rawIds))
+	for _, rawId := range rawIds {
+		userId, err := uuid.Parse(rawId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		userIds = append(userIds, userId)
+	}
+	return userIds, nil
+}
+
+func toUserResponse(user *models.User) *user_mgmt.UserResponse {
 	resp := &user_mgmt.UserResponse{
 		UserId:      user.Id.String(),
 		Name:        user.Name,
 		UrlTag:      user.UrlTag,
 		Description: user.Description,
 		ProfilePic:  user.ProfilePic,
+		StatusEmoji: user.StatusEmoji,
+		StatusText:  user.StatusText,
+		Pronouns:    user.Pronouns,
+		Links:       user.Links,
+		Timezone:    user.Timezone,
+	}
+	if user.StatusExpiresAt != nil {
+		resp.StatusExpiresAt = user.StatusExpiresAt.Unix()
+	}
+	if user.Birthday != nil {
+		resp.Birthday = user.Birthday.Format(time.DateOnly)
+	}
+	return resp
+}
+
+func (s *UserMgmtGRPCServer) GetUserSettings(ctx context.Context, req *user_mgmt.GetUserSettingsRequest) (*user_mgmt.UserSettingsResponse, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	settings, err := s.userMgmtService.GetSettings(userId)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &user_mgmt.UserSettingsResponse{
+		UserId:               settings.UserId.String(),
+		Language:             settings.Language,
+		Theme:                settings.Theme,
+		NotificationsEnabled: settings.NotificationsEnabled,
+		NotificationSound:    settings.NotificationSound,
+		NotificationPreview:  settings.NotificationPreview,
+		AutoDownloadPhotos:   settings.AutoDownloadPhotos,
+		AutoDownloadVideos:   settings.AutoDownloadVideos,
+		AutoDownloadFiles:    settings.AutoDownloadFiles,
+		AutoDownloadMaxSize:  settings.AutoDownloadMaxSize,
+		Version:              settings.Version,
+	}, nil
+}
+
+func (s *UserMgmtGRPCServer) GetChatNotificationSettings(ctx context.Context, req *user_mgmt.GetChatNotificationSettingsRequest) (*user_mgmt.GetChatNotificationSettingsResponse, error) {
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	chatIds := make([]uuid.UUID, 0, len(req.ChatIds))
+	for _, rawChatId := range req.ChatIds {
+		chatId, err := uuid.Parse(rawChatId)
+		if err != nil {
+			slog.Error(err.Error())
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		chatIds = append(chatIds, chatId)
+	}
+	settings, err := s.userMgmtService.GetChatNotificationSettings(userId, chatIds)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	now := time.Now()
+	resp := &user_mgmt.GetChatNotificationSettingsResponse{UserId: req.UserId}
+	for _, chatSettings := range settings {
+		chatResp := &user_mgmt.ChatNotificationSettingsResponse{
+			ChatId:      chatSettings.ChatId.String(),
+			Muted:       chatSettings.IsMuted(now),
+			Sound:       chatSettings.Sound,
+			ShowPreview: chatSettings.ShowPreview,
+			Version:     chatSettings.Version,
+		}
+		if chatSettings.MuteUntil != nil {
+			chatResp.MuteUntil = chatSettings.MuteUntil.Unix()
+		}
+		resp.Settings = append(resp.Settings, chatResp)
+	}
+	return resp, nil
+}
+
+func (s *UserMgmtGRPCServer) ListReports(ctx context.Context, req *user_mgmt.ListReportsRequest) (*user_mgmt.ListReportsResponse, error) {
+	_, err := s.authorizeModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = validator.ValidateReportStatus(req.Status)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	reports, err := s.userMgmtService.GetReports(req.Status)
+	if err != nil {
+		slog.Error(err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &user_mgmt.ListReportsResponse{}
+	for _, report := range reports {
+		resp.Reports = append(resp.Reports, &user_mgmt.ReportResponse{
+			ReportId:   report.Id.String(),
+			ReporterId: report.ReporterId.String(),
+			TargetType: report.TargetType,
+			TargetId:   report.TargetId.String(),
+			Reason:     report.Reason,
+			Details:    report.Details,
+			Status:     report.Status,
+			CreatedAt:  report.CreatedAt.Unix(),
+		})
 	}
 	return resp, nil
 }
+
+func (s *UserMgmtGRPCServer) ApplyModerationAction(ctx context.Context, req *user_mgmt.ModerationActionRequest) (*user_mgmt.ModerationActionResponse, error) {
+	authResp, err := s.authorizeModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	moderatorId, err := uuid.Parse(authResp.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	err = validator.ValidateModerationAction(req.Action)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	actionReq := &dto.ModerationActionRequest{
+		Action:   req.Action,
+		TargetId: req.TargetId,
+		ReportId: req.ReportId,
+		Reason:   req.Reason,
+	}
+	if req.SuspendedUntil != 0 {
+		until := time.Unix(req.SuspendedUntil, 0)
+		actionReq.SuspendedUntil = &until
+	}
+	outCtx := client.WithAuthorization(ctx, authResp.AccessToken)
+	action, err := s.userMgmtService.ApplyModerationAction(outCtx, moderatorId, actionReq)
+	if err != nil {
+		slog.Error(err.Error())
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrReportNotOpen) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp := &user_mgmt.ModerationActionResponse{
+		ActionId:    action.Id.String(),
+		ModeratorId: action.ModeratorId.String(),
+		Action:      action.Action,
+		TargetType:  action.TargetType,
+		TargetId:    action.TargetId.String(),
+		Reason:      action.Reason,
+		CreatedAt:   action.CreatedAt.Unix(),
+	}
+	if action.ReportId != nil {
+		resp.ReportId = action.ReportId.String()
+	}
+	return resp, nil
+}
+
+func (s *UserMgmtGRPCServer) authorizeModerator(ctx context.Context) (*auth.AuthorizeResponse, error) {
+	authResp, err := s.authClient.PerformAuthorize(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !models.CanModerate(authResp.Role) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	}
+	return authResp, nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/repository"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReportNotOpen      = errors.New("report is not open")
+	ErrReportRequired     = errors.New("report id is required")
+	ErrSuspensionInFuture = errors.New("suspension end must be in the future")
+)
+
+var permanentSuspension = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+func (s *UserMgmtService) CreateReport(reporterId uuid.UUID, req *dto.CreateReportRequest) (*models.Report, error) {
+	targetId, err := uuid.Parse(req.TargetId)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.Report{
+		Id:         uuid.New(),
+		ReporterId: reporterId,
+		TargetType: req.TargetType,
+		TargetId:   targetId,
+		Reason:     req.Reason,
+		Details:    req.Details,
+		Status:     models.ReportStatusOpen,
+	}
+	err = s.Repository.InsertReport(report)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info(fmt.Sprintf("Report %v on %s %v created by %v", report.Id, report.TargetType, report.TargetId, reporterId))
+	return report, nil
+}
+
+func (s *UserMgmtService) GetReports(status string) ([]models.Report, error) {
+	return s.Repository.GetReports(status)
+}
+
+func (s *UserMgmtService) GetModerationActions(targetId *uuid.UUID) ([]models.ModerationAction, error) {
+	return s.Repository.GetModerationActions(targetId)
+}
+
+// ctx must carry the moderator's authorization so that auth and chat can
+// check the role themselves.
+func (s *UserMgmtService) ApplyModerationAction(ctx context.Context, moderatorId uuid.UUID, req *dto.ModerationActionRequest) (*models.ModerationAction, error) {
+	action := &models.ModerationAction{
+		Id:          uuid.New(),
+		ModeratorId: moderatorId,
+		Action:      req.Action,
+		Reason:      req.Reason,
+	}
+	if req.ReportId != "" {
+		reportId, err := uuid.Parse(req.ReportId)
+		if err != nil {
+			return nil, err
+		}
+		action.ReportId = &reportId
+	}
+	if req.Action == models.ModerationActionDismissReport {
+		if action.ReportId == nil {
+			return nil, ErrReportRequired
+		}
+		action.TargetType = "report"
+		action.TargetId = *action.ReportId
+	} else {
+		targetId, err := uuid.Parse(req.TargetId)
+		if err != nil {
+			return nil, err
+		}
+		action.TargetId = targetId
+	}
+
+	err := prepareModerationAction(action, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The report, the local changes and the pending action commit together.
+	// Other services are only called afterwards so that no remote call runs
+	// while the report is locked, and a closed report cannot trigger the
+	// action a second time.
+	var fileId string
+	err = s.Repository.Transaction(func(tx *repository.UserMgmtRepository) error {
+		if action.ReportId != nil {
+			report, err := tx.GetReportForUpdate(*action.ReportId)
+			if err != nil {
+				return err
+			}
+			if report.Status != models.ReportStatusOpen {
+				return ErrReportNotOpen
+			}
+			now := time.Now()
+			report.Status = models.ReportStatusActioned
+			if req.Action == models.ModerationActionDismissReport {
+				report.Status = models.ReportStatusDismissed
+			}
+			report.ResolvedBy = &moderatorId
+			report.ResolvedAt = &now
+			err = tx.UpdateReport(report)
+			if err != nil {
+				return err
+			}
+		}
+		if req.Action == models.ModerationActionRemoveProfilePic {
+			var err error
+			fileId, err = removeProfilePic(tx, action.TargetId)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.InsertModerationAction(action)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if action.Status == models.ModerationStatusPending {
+		err = s.applyModerationAction(ctx, action, fileId)
+		action.Status = models.ModerationStatusApplied
+		if err != nil {
+			action.Status = models.ModerationStatusFailed
+		}
+		statusErr := s.Repository.UpdateModerationActionStatus(action.Id, action.Status)
+		if statusErr != nil {
+			slog.Error("Failed to record moderation action status", "error", statusErr.Error(), "actionId", action.Id)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	slog.Info(fmt.Sprintf("Moderator %v applied %s to %s %v", moderatorId, action.Action, action.TargetType, action.TargetId))
+	return action, nil
+}
+
+// Fills in the target type and status before anything is stored.
+func prepareModerationAction(action *models.ModerationAction, req *dto.ModerationActionRequest) error {
+	action.Status = models.ModerationStatusPending
+	switch req.Action {
+	case models.ModerationActionSuspendUser:
+		action.TargetType = models.ReportTargetUser
+		until := permanentSuspension
+		if req.SuspendedUntil != nil {
+			until = *req.SuspendedUntil
+		}
+		if !until.After(time.Now()) {
+			return ErrSuspensionInFuture
+		}
+		action.SuspendedUntil = &until
+	case models.ModerationActionRemoveProfilePic:
+		action.TargetType = models.ReportTargetUser
+	case models.ModerationActionDeleteChat:
+		action.TargetType = models.ReportTargetChat
+	default:
+		action.Status = models.ModerationStatusApplied
+	}
+	return nil
+}
+
+func (s *UserMgmtService) applyModerationAction(ctx context.Context, action *models.ModerationAction, fileId string) error {
+	switch action.Action {
+	case models.ModerationActionSuspendUser:
+		_, err := s.authClient.PerformSetSuspension(ctx, action.TargetId.String(), action.SuspendedUntil.Unix())
+		if err != nil {
+			slog.Error("Failed to suspend user", "error", err.Error())
+			return err
+		}
+	case models.ModerationActionRemoveProfilePic:
+		if fileId == "" {
+			return nil
+		}
+		err := s.mediaClient.PerformDeleteMedia(ctx, fileId, action.TargetId.String())
+		if err != nil {
+			slog.Error("Failed to delete avatar media", "error", err.Error())
+			return err
+		}
+	case models.ModerationActionDeleteChat:
+		err := s.chatClient.PerformDeleteChat(ctx, action.TargetId.String())
+		if err != nil {
+			slog.Error("Failed to delete chat", "error", err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// Clears the picture and its avatar row. Returns the stored file to delete
+// from media_handler once the transaction commits, if there is one.
+func removeProfilePic(tx *repository.UserMgmtRepository, userId uuid.UUID) (string, error) {
+	user, err := tx.GetUser(userId)
+	if err != nil {
+		return "", err
+	}
+	if user.ProfilePic == "" {
+		return "", nil
+	}
+	fileId := user.ProfilePic
+	avatar, err := tx.GetAvatarByFileId(userId, fileId)
+	if err == nil {
+		err = tx.DeleteAvatar(avatar.Id)
+		if err != nil {
+			return "", err
+		}
+	}
+	user.ProfilePic = ""
+	err = tx.UpdateUser(user)
+	if err != nil {
+		return "", err
+	}
+	if avatar == nil {
+		return "", nil
+	}
+	return fileId, nil
+}
@@ -1,20 +1,28 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/client"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/dto"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/repository"
 	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
 	"github.com/google/uuid"
 )
 
 type UserMgmtService struct {
-	Repository *repository.UserMgmtRepository
+	Repository  *repository.UserMgmtRepository
+	mediaClient *client.MediaHandlerGRPCClient
+	authClient  *client.AuthGRPCClient
+	chatClient  *client.ChatGRPCClient
 }
 
-func New(repository *repository.UserMgmtRepository) *UserMgmtService {
-	return &UserMgmtService{Repository: repository}
+func New(repository *repository.UserMgmtRepository, mediaClient *client.MediaHandlerGRPCClient, authClient *client.AuthGRPCClient, chatClient *client.ChatGRPCClient) *UserMgmtService {
+	return &UserMgmtService{Repository: repository, mediaClient: mediaClient, authClient: authClient, chatClient: chatClient}
 }
 
 func (s *UserMgmtService) CreateUser(userId uuid.UUID, name string) (*models.User, error) {
@@ -24,19 +32,66 @@ func (s *UserMgmtService) CreateUser(userId uuid.UUID, name string) (*models.Use
 	return user, err
 }
 
-func (s *UserMgmtService) UpdateUser(userId uuid.UUID, name string, urlTag string, description string) (*models.User, error) {
+func (s *UserMgmtService) UpdateUser(userId uuid.UUID, req *dto.UpdateInfoRequest) (*models.User, error) {
 	user, err := s.GetUser(userId)
 	if err != nil {
 		return nil, err
 	}
-	user.Name = name
-	user.UrlTag = urlTag
-	user.Description = description
+	user.Name = req.Name
+	user.UrlTag = req.UrlTag
+	user.Description = req.Description
+	user.Pronouns = req.Pronouns
+	user.Links = req.Links
+	user.Timezone = req.Timezone
+	user.Birthday = nil
+	if req.Birthday != "" {
+		birthday, err := time.Parse(time.DateOnly, req.Birthday)
+		if err != nil {
+			return nil, err
+		}
+		user.Birthday = &birthday
+	}
+	err = s.Repository.UpdateUser(user)
+	return user, err
+}
+
+func (s *UserMgmtService) UpdateStatus(userId uuid.UUID, req *dto.UpdateStatusRequest) (*models.User, error) {
+	user, err := s.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	user.StatusEmoji = req.StatusEmoji
+	user.StatusText = req.StatusText
+	user.StatusExpiresAt = req.ExpiresAt
+	if user.StatusEmoji == "" && user.StatusText == "" {
+		user.ClearStatus()
+	}
 	err = s.Repository.UpdateUser(user)
 	return user, err
 }
 
-func (s *UserMgmtService) DeleteUser(userId uuid.UUID) error {
+func (s *UserMgmtService) RunStatusSweeper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		cleared, err := s.Repository.ClearExpiredStatuses(now)
+		if err != nil {
+			slog.Error("Failed to clear expired statuses", "error", err.Error())
+			continue
+		}
+		if cleared > 0 {
+			slog.Info(fmt.Sprintf("Cleared %d expired statuses", cleared))
+		}
+	}
+}
+
+// Leaves every chat first so the chat service can hand owned chats over.
+func (s *UserMgmtService) DeleteUser(ctx context.Context, userId uuid.UUID) error {
+	err := s.chatClient.PerformLeaveAllChats(ctx, userId.String())
+	if err != nil {
+		slog.Error("Failed to leave chats", "error", err.Error(), "userId", userId)
+		return err
+	}
 	return s.Repository.DeleteUser(userId)
 }
 
@@ -45,9 +100,45 @@ func (s *UserMgmtService) GetUser(userId uuid.UUID) (*models.User, error) {
 	if err != nil {
 		return nil, err
 	}
+	if user.IsStatusExpired(time.Now()) {
+		user.ClearStatus()
+	}
 	return user, nil
 }
 
+func (s *UserMgmtService) GetAllUsers() ([]models.User, error) {
+	users, err := s.Repository.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range users {
+		if users[i].IsStatusExpired(now) {
+			users[i].ClearStatus()
+		}
+	}
+	return users, nil
+}
+
+func (s *UserMgmtService) SearchUsers(query string, userIds []uuid.UUID, limit int) ([]models.User, error) {
+	return s.Repository.SearchUsers(query, userIds, limit)
+}
+
+// Unknown ids are skipped.
+func (s *UserMgmtService) GetUsersByIds(userIds []uuid.UUID) ([]models.User, error) {
+	users, err := s.Repository.GetUsersByIds(userIds)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range users {
+		if users[i].IsStatusExpired(now) {
+			users[i].ClearStatus()
+		}
+	}
+	return users, nil
+}
+
 func (s *UserMgmtService) GetUserByUrlTag(urlTag string) (*models.User, error) {
 	user, err := s.Repository.GetUserByUrlTag(urlTag)
 	if err != nil {
@@ -56,12 +147,84 @@ func (s *UserMgmtService) GetUserByUrlTag(urlTag string) (*models.User, error) {
 	return user, nil
 }
 
-func (s *UserMgmtService) UpdateAvatar(userId uuid.UUID, newFileId string) (*models.User, error) {
+func (s *UserMgmtService) UpdateAvatar(ctx context.Context, userId uuid.UUID, newFileId string) (*models.User, error) {
 	user, err := s.GetUser(userId)
 	if err != nil {
 		return nil, err
 	}
+	_, err = s.mediaClient.PerformVerifyAvatar(ctx, newFileId, userId.String())
+	if err != nil {
+		slog.Error("Failed to verify avatar", "error", err.Error())
+		return nil, err
+	}
+	avatar := &models.Avatar{Id: uuid.New(), UserId: userId, FileId: newFileId}
+	err = s.Repository.InsertAvatar(avatar)
+	if err != nil {
+		return nil, err
+	}
 	user.ProfilePic = newFileId
 	err = s.Repository.UpdateUser(user)
 	return user, err
 }
+
+func (s *UserMgmtService) GetAvatars(userId uuid.UUID) ([]models.Avatar, error) {
+	return s.Repository.GetAvatars(userId)
+}
+
+func (s *UserMgmtService) SetCurrentAvatar(userId uuid.UUID, avatarId uuid.UUID) (*models.User, error) {
+	avatar, err := s.getOwnAvatar(userId, avatarId)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	user.ProfilePic = avatar.FileId
+	err = s.Repository.UpdateUser(user)
+	return user, err
+}
+
+func (s *UserMgmtService) DeleteAvatar(ctx context.Context, userId uuid.UUID, avatarId uuid.UUID) (*models.User, error) {
+	avatar, err := s.getOwnAvatar(userId, avatarId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.mediaClient.PerformDeleteMedia(ctx, avatar.FileId, userId.String())
+	if err != nil {
+		slog.Error("Failed to delete avatar media", "error", err.Error())
+		return nil, err
+	}
+	err = s.Repository.DeleteAvatar(avatar.Id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.ProfilePic != avatar.FileId {
+		return user, nil
+	}
+	user.ProfilePic = ""
+	avatars, err := s.Repository.GetAvatars(userId)
+	if err != nil {
+		return nil, err
+	}
+	if len(avatars) > 0 {
+		user.ProfilePic = avatars[0].FileId
+	}
+	err = s.Repository.UpdateUser(user)
+	return user, err
+}
+
+func (s *UserMgmtService) getOwnAvatar(userId uuid.UUID, avatarId uuid.UUID) (*models.Avatar, error) {
+	avatar, err := s.Repository.GetAvatar(avatarId)
+	if err != nil {
+		return nil, err
+	}
+	if avatar.UserId != userId {
+		return nil, errors.New("avatar does not belong to user")
+	}
+	return avatar, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"time"
+
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/dto"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/internal/repository"
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+func (s *UserMgmtService) GetSettings(userId uuid.UUID) (*models.Settings, error) {
+	settings, err := s.Repository.GetSettings(userId)
+	if gorm.IsRecordNotFoundError(err) {
+		return models.DefaultSettings(userId), nil
+	}
+	return settings, err
+}
+
+// Writes always win. A stale version only means another device changed the
+// settings in between, so the overwritten state is reported back.
+func (s *UserMgmtService) UpdateSettings(userId uuid.UUID, req *dto.UpdateSettingsRequest) (*dto.SettingsResponse, error) {
+	resp := &dto.SettingsResponse{}
+	err := s.Repository.Transaction(func(tx *repository.UserMgmtRepository) error {
+		settings, err := tx.GetSettingsForUpdate(userId)
+		if gorm.IsRecordNotFoundError(err) {
+			settings = models.DefaultSettings(userId)
+		} else if err != nil {
+			return err
+		}
+		if req.Version != settings.Version {
+			overwritten := *settings
+			resp.Conflict = true
+			resp.ConflictingDevice = settings.UpdatedBy
+			resp.Overwritten = &overwritten
+		}
+		settings.Language = req.Language
+		settings.Theme = req.Theme
+		settings.NotificationsEnabled = req.NotificationsEnabled
+		settings.NotificationSound = req.NotificationSound
+		settings.NotificationPreview = req.NotificationPreview
+		settings.AutoDownloadPhotos = req.AutoDownloadPhotos
+		settings.AutoDownloadVideos = req.AutoDownloadVideos
+		settings.AutoDownloadFiles = req.AutoDownloadFiles
+		settings.AutoDownloadMaxSize = req.AutoDownloadMaxSize
+		settings.Version++
+		settings.UpdatedBy = req.DeviceId
+		settings.UpdatedAt = time.Now()
+		resp.Settings = settings
+		return tx.SaveSettings(settings)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *UserMgmtService) GetChatNotificationSettings(userId uuid.UUID, chatIds []uuid.UUID) ([]*models.ChatNotificationSettings, error) {
+	stored, err := s.Repository.GetChatNotificationSettings(userId, chatIds)
+	if err != nil {
+		return nil, err
+	}
+	byChat := make(map[uuid.UUID]*models.ChatNotificationSettings, len(stored))
+	for i := range stored {
+		byChat[stored[i].ChatId] = &stored[i]
+	}
+	settings := make([]*models.ChatNotificationSettings, 0, len(chatIds))
+	for _, chatId := range chatIds {
+		if chatSettings, ok := byChat[chatId]; ok {
+			settings = append(settings, chatSettings)
+		} else {
+			settings = append(settings, models.DefaultChatNotificationSettings(userId, chatId))
+		}
+	}
+	return settings, nil
+}
+
+func (s *UserMgmtService) UpdateChatNotificationSettings(userId uuid.UUID, chatId uuid.UUID, req *dto.UpdateChatNotificationSettingsRequest) (*dto.ChatNotificationSettingsResponse, error) {
+	resp := &dto.ChatNotificationSettingsResponse{}
+	err := s.Repository.Transaction(func(tx *repository.UserMgmtRepository) error {
+		settings, err := tx.GetChatNotificationSettingsForUpdate(userId, chatId)
+		if gorm.IsRecordNotFoundError(err) {
+			settings = models.DefaultChatNotificationSettings(userId, chatId)
+		} else if err != nil {
+			return err
+		}
+		if req.Version != settings.Version {
+			overwritten := *settings
+			resp.Conflict = true
+			resp.ConflictingDevice = settings.UpdatedBy
+			resp.Overwritten = &overwritten
+		}
+		settings.MuteUntil = req.MuteUntil
+		settings.Sound = req.Sound
+		settings.ShowPreview = req.ShowPreview
+		settings.Version++
+		settings.UpdatedBy = req.DeviceId
+		settings.UpdatedAt = time.Now()
+		resp.Settings = settings
+		return tx.SaveChatNotificationSettings(settings)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
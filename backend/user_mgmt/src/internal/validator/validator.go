@@ -2,7 +2,12 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PolyTechProjects/chaotic_chat/user_mgmt/src/models"
 )
 
 func ValidateName(name string) error {
@@ -49,3 +54,195 @@ func ValidateDescription(description string) error {
 	}
 	return nil
 }
+
+func ValidateStatusEmoji(emoji string) error {
+	if utf8.RuneCountInString(emoji) > 8 {
+		return fmt.Errorf("status emoji %s is too long", emoji)
+	}
+	for _, c := range emoji {
+		if c < 0x80 {
+			return fmt.Errorf("status emoji %s contains forbidden characters", emoji)
+		}
+	}
+	return nil
+}
+
+func ValidateStatusText(text string) error {
+	if strings.TrimSpace(text) == "" && len(text) != 0 {
+		return fmt.Errorf("status text %s is blank", text)
+	}
+	if utf8.RuneCountInString(text) > 100 {
+		return fmt.Errorf("status text %s is too short or too long", text)
+	}
+	for _, c := range text {
+		if c <= 0x1F {
+			return fmt.Errorf("status text %s contains forbidden characters", text)
+		}
+	}
+	return nil
+}
+
+func ValidateStatusExpiresAt(expiresAt *time.Time) error {
+	if expiresAt == nil {
+		return nil
+	}
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return fmt.Errorf("status expiration time %v is in the past", expiresAt)
+	}
+	if expiresAt.After(now.AddDate(1, 0, 0)) {
+		return fmt.Errorf("status expiration time %v is too far in the future", expiresAt)
+	}
+	return nil
+}
+
+func ValidateBirthday(birthday string) error {
+	if len(birthday) == 0 {
+		return nil
+	}
+	date, err := time.Parse(time.DateOnly, birthday)
+	if err != nil {
+		return fmt.Errorf("birthday %s is not a valid date", birthday)
+	}
+	if date.Year() < 1900 || date.After(time.Now()) {
+		return fmt.Errorf("birthday %s is out of range", birthday)
+	}
+	return nil
+}
+
+func ValidatePronouns(pronouns string) error {
+	if strings.TrimSpace(pronouns) == "" && len(pronouns) != 0 {
+		return fmt.Errorf("pronouns %s are blank", pronouns)
+	}
+	if utf8.RuneCountInString(pronouns) > 32 {
+		return fmt.Errorf("pronouns %s are too short or too long", pronouns)
+	}
+	for _, c := range pronouns {
+		if c <= 0x1F {
+			return fmt.Errorf("pronouns %s contain forbidden characters", pronouns)
+		}
+	}
+	return nil
+}
+
+func ValidateLinks(links []string) error {
+	if len(links) > 5 {
+		return fmt.Errorf("too many links: %d", len(links))
+	}
+	for _, link := range links {
+		if len(link) > 255 {
+			return fmt.Errorf("link %s is too long", link)
+		}
+		u, err := url.ParseRequestURI(link)
+		if err != nil {
+			return fmt.Errorf("link %s is not a valid URL", link)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("link %s must be an absolute http(s) URL", link)
+		}
+	}
+	return nil
+}
+
+func ValidateTimezone(timezone string) error {
+	if len(timezone) == 0 {
+		return nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+		return fmt.Errorf("timezone %s is unknown", timezone)
+	}
+	return nil
+}
+
+func ValidateLanguage(language string) error {
+	parts := strings.Split(language, "-")
+	if len(parts) > 2 || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return fmt.Errorf("language %s is not a valid language tag", language)
+	}
+	for _, part := range parts {
+		if len(part) < 2 || len(part) > 4 {
+			return fmt.Errorf("language %s is not a valid language tag", language)
+		}
+		for _, c := range part {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+				return fmt.Errorf("language %s contains forbidden characters", language)
+			}
+		}
+	}
+	return nil
+}
+
+func ValidateTheme(theme string) error {
+	if theme != "light" && theme != "dark" && theme != "system" {
+		return fmt.Errorf("theme %s is unknown", theme)
+	}
+	return nil
+}
+
+func ValidateAutoDownloadRule(rule string) error {
+	if rule != models.AutoDownloadNever && rule != models.AutoDownloadWifi && rule != models.AutoDownloadAlways {
+		return fmt.Errorf("auto download rule %s is unknown", rule)
+	}
+	return nil
+}
+
+func ValidateAutoDownloadMaxSize(maxSize int64) error {
+	if maxSize < 0 {
+		return fmt.Errorf("auto download max size %d is negative", maxSize)
+	}
+	return nil
+}
+
+func ValidateDeviceId(deviceId string) error {
+	if strings.TrimSpace(deviceId) == "" {
+		return fmt.Errorf("device id %s is blank", deviceId)
+	}
+	if len(deviceId) > 64 {
+		return fmt.Errorf("device id %s is too short or too long", deviceId)
+	}
+	for _, c := range deviceId {
+		if c <= 0x1F {
+			return fmt.Errorf("device id %s contains forbidden characters", deviceId)
+		}
+	}
+	return nil
+}
+
+func ValidateReportTargetType(targetType string) error {
+	if targetType != models.ReportTargetUser && targetType != models.ReportTargetChat && targetType != models.ReportTargetMessage {
+		return fmt.Errorf("report target type %s is unknown", targetType)
+	}
+	return nil
+}
+
+func ValidateReportReason(reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("report reason %s is blank", reason)
+	}
+	if len(reason) > 64 {
+		return fmt.Errorf("report reason %s is too long", reason)
+	}
+	return nil
+}
+
+func ValidateReportDetails(details string) error {
+	if len(details) > 1000 {
+		return fmt.Errorf("report details are too long")
+	}
+	return nil
+}
+
+func ValidateReportStatus(status string) error {
+	if status != "" && status != models.ReportStatusOpen && status != models.ReportStatusActioned && status != models.ReportStatusDismissed {
+		return fmt.Errorf("report status %s is unknown", status)
+	}
+	return nil
+}
+
+func ValidateModerationAction(action string) error {
+	switch action {
+	case models.ModerationActionSuspendUser, models.ModerationActionRemoveProfilePic, models.ModerationActionDeleteChat, models.ModerationActionDismissReport:
+		return nil
+	}
+	return fmt.Errorf("moderation action %s is unknown", action)
+}
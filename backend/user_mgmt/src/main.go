@@ -23,8 +23,11 @@ func main() {
 	database.Init(cfg)
 	db := database.DB
 	authClient := client.NewAuthClient(cfg)
+	mediaClient := client.NewMediaHandlerClient(cfg)
+	chatClient := client.NewChatClient(cfg)
 	repository := repository.New(db)
-	service := service.New(repository)
+	service := service.New(repository, mediaClient, authClient, chatClient)
+	go service.RunStatusSweeper(cfg.App.StatusSweepInterval)
 	controller := controller.New(service, authClient)
 	httpServer := server.NewHttpServer(controller)
 	grpcServer := server.NewGRPCServer(service, authClient)
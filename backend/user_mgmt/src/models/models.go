@@ -1,17 +1,154 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const RoleAdmin = "admin"
+
 type User struct {
-	Id          uuid.UUID `gorm:"primary_key;type:uuid;default:gen_random_uuid()"`
-	Name        string    `gorm:"not null" json:"name"`
-	UrlTag      string    `gorm:"unique;not null;check:url_tag <> ''" json:"url_tag"`
-	Description string    `json:"description"`
-	ProfilePic  string    `json:"profile_pic"`
+	Id              uuid.UUID      `gorm:"primary_key;type:uuid;default:gen_random_uuid()"`
+	Name            string         `gorm:"not null" json:"name"`
+	UrlTag          string         `gorm:"unique;not null;check:url_tag <> ''" json:"url_tag"`
+	Description     string         `json:"description"`
+	ProfilePic      string         `json:"profile_pic"`
+	StatusEmoji     string         `json:"status_emoji"`
+	StatusText      string         `json:"status_text"`
+	StatusExpiresAt *time.Time     `json:"status_expires_at"`
+	Birthday        *time.Time     `gorm:"type:date" json:"birthday"`
+	Pronouns        string         `json:"pronouns"`
+	Links           pq.StringArray `gorm:"type:text[]" json:"links"`
+	Timezone        string         `json:"timezone"`
 }
 
 func New(id uuid.UUID, name string) *User {
 	return &User{Id: id, Name: name, UrlTag: id.String(), Description: "", ProfilePic: ""}
 }
+
+func (u *User) ClearStatus() {
+	u.StatusEmoji = ""
+	u.StatusText = ""
+	u.StatusExpiresAt = nil
+}
+
+func (u *User) IsStatusExpired(now time.Time) bool {
+	return u.StatusExpiresAt != nil && !u.StatusExpiresAt.After(now)
+}
+
+type Avatar struct {
+	Id        uuid.UUID `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserId    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	FileId    string    `gorm:"not null;check:file_id <> ''" json:"file_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const (
+	AutoDownloadNever  = "never"
+	AutoDownloadWifi   = "wifi"
+	AutoDownloadAlways = "always"
+)
+
+type Settings struct {
+	UserId               uuid.UUID `gorm:"primary_key;type:uuid" json:"user_id"`
+	Language             string    `gorm:"not null;default:'en'" json:"language"`
+	Theme                string    `gorm:"not null;default:'system'" json:"theme"`
+	NotificationsEnabled bool      `gorm:"not null" json:"notifications_enabled"`
+	NotificationSound    bool      `gorm:"not null" json:"notification_sound"`
+	NotificationPreview  bool      `gorm:"not null" json:"notification_preview"`
+	AutoDownloadPhotos   string    `gorm:"not null;default:'always'" json:"auto_download_photos"`
+	AutoDownloadVideos   string    `gorm:"not null;default:'wifi'" json:"auto_download_videos"`
+	AutoDownloadFiles    string    `gorm:"not null;default:'wifi'" json:"auto_download_files"`
+	AutoDownloadMaxSize  int64     `gorm:"not null;default:0" json:"auto_download_max_size"`
+	Version              int64     `gorm:"not null;default:0" json:"version"`
+	UpdatedBy            string    `json:"updated_by"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+func DefaultSettings(userId uuid.UUID) *Settings {
+	return &Settings{
+		UserId:               userId,
+		Language:             "en",
+		Theme:                "system",
+		NotificationsEnabled: true,
+		NotificationSound:    true,
+		NotificationPreview:  true,
+		AutoDownloadPhotos:   AutoDownloadAlways,
+		AutoDownloadVideos:   AutoDownloadWifi,
+		AutoDownloadFiles:    AutoDownloadWifi,
+	}
+}
+
+type ChatNotificationSettings struct {
+	UserId      uuid.UUID  `gorm:"primary_key;type:uuid" json:"user_id"`
+	ChatId      uuid.UUID  `gorm:"primary_key;type:uuid" json:"chat_id"`
+	MuteUntil   *time.Time `json:"mute_until"`
+	Sound       bool       `gorm:"not null" json:"sound"`
+	ShowPreview bool       `gorm:"not null" json:"show_preview"`
+	Version     int64      `gorm:"not null;default:0" json:"version"`
+	UpdatedBy   string     `json:"updated_by"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func DefaultChatNotificationSettings(userId uuid.UUID, chatId uuid.UUID) *ChatNotificationSettings {
+	return &ChatNotificationSettings{UserId: userId, ChatId: chatId, Sound: true, ShowPreview: true}
+}
+
+func (s *ChatNotificationSettings) IsMuted(now time.Time) bool {
+	return s.MuteUntil != nil && s.MuteUntil.After(now)
+}
+
+const (
+	RoleModerator = "moderator"
+
+	ReportTargetUser    = "user"
+	ReportTargetChat    = "chat"
+	ReportTargetMessage = "message"
+
+	ReportStatusOpen      = "open"
+	ReportStatusActioned  = "actioned"
+	ReportStatusDismissed = "dismissed"
+
+	ModerationActionSuspendUser      = "suspend_user"
+	ModerationActionRemoveProfilePic = "remove_profile_pic"
+	ModerationActionDeleteChat       = "delete_chat"
+	ModerationActionDismissReport    = "dismiss_report"
+
+	// Actions are recorded as pending before auth, chat or media_handler are
+	// called and settle once they answer.
+	ModerationStatusPending = "pending"
+	ModerationStatusApplied = "applied"
+	ModerationStatusFailed  = "failed"
+)
+
+func CanModerate(role string) bool {
+	return role == RoleModerator || role == RoleAdmin
+}
+
+type Report struct {
+	Id         uuid.UUID  `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	ReporterId uuid.UUID  `gorm:"type:uuid;not null" json:"reporter_id"`
+	TargetType string     `gorm:"not null;check:target_type <> ''" json:"target_type"`
+	TargetId   uuid.UUID  `gorm:"type:uuid;not null;index" json:"target_id"`
+	Reason     string     `gorm:"not null;check:reason <> ''" json:"reason"`
+	Details    string     `json:"details"`
+	Status     string     `gorm:"not null;default:'open';index" json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedBy *uuid.UUID `gorm:"type:uuid" json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
+
+type ModerationAction struct {
+	Id             uuid.UUID  `gorm:"primary_key;type:uuid;default:gen_random_uuid()" json:"id"`
+	ModeratorId    uuid.UUID  `gorm:"type:uuid;not null;index" json:"moderator_id"`
+	ReportId       *uuid.UUID `gorm:"type:uuid" json:"report_id"`
+	Action         string     `gorm:"not null;check:action <> ''" json:"action"`
+	TargetType     string     `gorm:"not null" json:"target_type"`
+	TargetId       uuid.UUID  `gorm:"type:uuid;not null;index" json:"target_id"`
+	Reason         string     `json:"reason"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	Status         string     `gorm:"not null;default:'applied'" json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+}